@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/checker"
+	"github.com/kriptonian1/BroLang/src/module"
+	"github.com/kriptonian1/BroLang/src/token"
+	"github.com/kriptonian1/BroLang/src/translate"
+)
+
+/*
+runCommand runs a subcommand given on the command line (e.g. broLang check main.bro)
+
+@param args []string - The arguments after the global flags, starting with the subcommand
+
+@param out io.Writer - Where results and errors are written
+
+@return int - The exit code
+*/
+func runCommand(args []string, out io.Writer) int {
+	switch args[0] {
+	case "check":
+		return runCheck(args[1:], out)
+	case "types":
+		return runTypes(args[1:], out)
+	case "translate":
+		return runTranslate(args[1:], out)
+	default:
+		fmt.Fprintf(out, "Unknown command: %s\n", args[0])
+		fmt.Fprintln(out, "Available commands: check, types, translate")
+		return 2
+	}
+}
+
+// newLoader creates a module loader for the search path in BROPATH and the dialect chosen with -dialect
+func newLoader() *module.Loader {
+	loader := module.NewLoader(module.SearchPathFromEnv())
+	loader.Dialect = dialect
+	return loader
+}
+
+/*
+runCheck type checks a source file without running it: broLang check [--infer] file.bro.
+With --infer the types of unannotated code are inferred too, which is stricter.
+*/
+func runCheck(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(out)
+	infer := fs.Bool("infer", false, "Infer the types of unannotated lets and functions")
+	fs.Usage = func() {
+		fmt.Fprintln(out, "Usage: broLang check [--infer] file.bro")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	path := fs.Arg(0)
+	mod, err := newLoader().Load(path)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	var errs []error
+	if *infer {
+		_, errs = checker.Infer(mod.Program)
+	} else {
+		errs = checker.Check(mod.Program)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(out, "%s: %s\n", path, err)
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+	return 0
+}
+
+/*
+runTypes prints the inferred types of a source file: broLang types file.bro lists the
+top-level names, and broLang types file.bro line:col prints the type of the name at
+that position, the way an editor shows it on hover.
+*/
+func runTypes(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("types", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.Usage = func() { fmt.Fprintln(out, "Usage: broLang types file.bro [line:col]") }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 2
+	}
+
+	path := fs.Arg(0)
+	mod, err := newLoader().Load(path)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	inference, errs := checker.Infer(mod.Program)
+
+	if fs.NArg() == 2 { // A query only answers with the type, like an editor tooltip
+		var line, col int
+		if _, err := fmt.Sscanf(fs.Arg(1), "%d:%d", &line, &col); err != nil {
+			fmt.Fprintf(out, "invalid position %q, expected line:col\n", fs.Arg(1))
+			return 2
+		}
+		typ, ok := inference.TypeAt(line, col)
+		if !ok {
+			fmt.Fprintf(out, "%s: no name at %d:%d\n", path, line, col)
+			return 1
+		}
+		fmt.Fprintln(out, typ)
+		return 0
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(out, "%s: %s\n", path, err)
+	}
+	for _, b := range inference.Bindings {
+		fmt.Fprintf(out, "%d:%d %s: %s\n", b.Token.Line, b.Token.Column, b.Name, b.Type)
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+	return 0
+}
+
+/*
+runTranslate converts a source file to another keyword dialect: broLang translate --to bro file.bro.
+The result is printed, or written back to the file with -w.
+*/
+func runTranslate(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("translate", flag.ContinueOnError)
+	fs.SetOutput(out)
+	to := fs.String("to", "", "The dialect to translate into ("+strings.Join(token.DialectNames(), ", ")+")")
+	write := fs.Bool("w", false, "Write the result back to the file instead of printing it")
+	fs.Usage = func() {
+		fmt.Fprintln(out, "Usage: broLang translate --to dialect [-w] file.bro")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	target, ok := token.LookupDialect(*to)
+	if fs.NArg() != 1 || !ok {
+		fs.Usage()
+		return 2
+	}
+
+	path := fs.Arg(0)
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	translated, err := translate.Translate(string(source), dialect, target)
+	if err != nil {
+		fmt.Fprintf(out, "%s: %s\n", path, err)
+		return 1
+	}
+
+	if *write {
+		if err := os.WriteFile(path, []byte(translated), 0o644); err != nil {
+			fmt.Fprintln(out, err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprint(out, translated)
+	return 0
+}
@@ -6,9 +6,15 @@ import (
 	"os"
 	"os/user"
 
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/lexer"
 	"github.com/kriptonian1/BroLang/src/repl"
+	"github.com/kriptonian1/BroLang/src/token"
 )
 
+var dialect = token.Classic // The dialect of source without a dialect pragma, set with -dialect
+
 func init() {
 
 	version := "0.0.1" // Define the version of BroLang
@@ -20,6 +26,8 @@ func init() {
 	aliasVersion := flag.Bool("version", false, "Alias for -v")
 	aliasHelp := flag.Bool("help", false, "Alias for -h")
 
+	dialectName := flag.String("dialect", token.Classic.Name, "Keyword dialect of source without a dialect pragma ("+strings.Join(token.DialectNames(), ", ")+")")
+
 	flag.Parse() // Parse the flags
 
 	// Check if the flags are true
@@ -32,10 +40,21 @@ func init() {
 		flag.Usage()
 		os.Exit(0)
 	}
+
+	d, ok := token.LookupDialect(*dialectName)
+	if !ok {
+		fmt.Printf("Unknown dialect: %s\n", *dialectName)
+		os.Exit(2)
+	}
+	dialect = d
 }
 
 func main() {
 
+	if flag.NArg() > 0 { // A subcommand (e.g. broLang check main.bro) runs instead of the REPL
+		os.Exit(runCommand(flag.Args(), os.Stdout))
+	}
+
 	user, err := user.Current()
 
 	if err != nil {
@@ -44,5 +63,5 @@ func main() {
 
 	fmt.Printf("Hello %s👋 This is the BroLang programming language!\n", user.Username)
 	fmt.Printf("Feel free to type in commands\n")
-	repl.Start(os.Stdin, os.Stdout)
+	repl.Start(os.Stdin, os.Stdout, lexer.WithDialect(dialect))
 }
@@ -0,0 +1,72 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+The AST node for arrow functions (e.g. x => x * 2 or (a, b) => a + b). An arrow function is
+a function literal whose body is a single expression; it is kept apart from FunctionLiteral
+so that the source can be printed back the way it was written.
+*/
+type ArrowFunction struct {
+	Token         token.Token  // The token.FAT_ARROW token
+	Parameters    []*Parameter // The parameters, which are plain names
+	Body          Expression   // The value the function returns
+	Parenthesized bool         // True if the parameters were written in parentheses, as (x) => x
+}
+
+func (af *ArrowFunction) expressionNode()      {}
+func (af *ArrowFunction) TokenLiteral() string { return af.Token.Literal }
+
+// Returns the arrow function as written (e.g. x => (x * 2) or (a, b) => (a + b))
+func (af *ArrowFunction) String() string {
+	params := []string{}
+	for _, p := range af.Parameters {
+		params = append(params, p.String())
+	}
+	if af.Parenthesized || len(params) != 1 {
+		return "(" + strings.Join(params, ", ") + ") => " + af.Body.String()
+	}
+	return params[0] + " => " + af.Body.String()
+}
+
+// Block returns the body as the block of the equivalent function literal, whose only statement is the body
+func (af *ArrowFunction) Block() *BlockStatement {
+	stmt := &ExpressionStatement{Token: af.Token, Expression: af.Body}
+	return &BlockStatement{Token: af.Token, Statements: []Statement{stmt}}
+}
+
+/*
+The AST node for the pipeline operator (e.g. data |> filter(isValid)), which passes Left
+as the first argument of the call on its right: data |> filter(isValid) |> map(f) means
+map(filter(data, isValid), f). A right side that is not a call is called with Left alone.
+*/
+type PipelineExpression struct {
+	Token token.Token // The token.PIPE token
+	Left  Expression  // The value piped in
+	Right Expression  // The call, or the function, it is piped into
+	Tail  bool        // True if the call the pipeline stands for is a tail call (see MarkTailCalls)
+}
+
+func (pe *PipelineExpression) expressionNode()      {}
+func (pe *PipelineExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PipelineExpression) String() string {
+	return "(" + pe.Left.String() + " |> " + pe.Right.String() + ")"
+}
+
+// Desugar returns the nested calls the pipeline stands for; the pipeline itself is not changed
+func (pe *PipelineExpression) Desugar() *CallExpression {
+	left := pe.Left
+	if inner, ok := left.(*PipelineExpression); ok {
+		left = inner.Desugar()
+	}
+
+	if call, ok := pe.Right.(*CallExpression); ok {
+		args := append([]Expression{left}, call.Arguments...)
+		return &CallExpression{Token: call.Token, Function: call.Function, Arguments: args, Tail: pe.Tail}
+	}
+	return &CallExpression{Token: pe.Token, Function: pe.Right, Arguments: []Expression{left}, Tail: pe.Tail}
+}
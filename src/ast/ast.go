@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
+	"strings"
 
 	"github.com/kriptonian1/BroLang/src/token"
 )
@@ -48,11 +50,19 @@ func (p *Program) TokenLiteral() string { // Returns the token literal of the fi
 	}
 }
 
-// The AST node for the let statement
+/*
+The AST node for the let statement.
+
+A plain let x = ...; only sets Name. A destructuring let [a, b] = ...; or
+let {name} = ...; leaves Name nil and sets Pattern instead.
+*/
 type LetStatement struct {
-	Token token.Token // The token.LET token
-	Name  *Identifier // The identifier of the variable
-	Value Expression  // The value of the variable
+	Token   token.Token    // The token.LET token
+	Name    *Identifier    // The identifier of the variable, nil when destructuring
+	Pattern Pattern        // The array or hash pattern being destructured, nil for a plain identifier
+	Type    TypeAnnotation // The type written after the name (e.g. let x: int = 5;), nil if there is none
+	Value   Expression     // The value of the variable
+	Doc     string         // The /// doc comment above the statement, one line per comment (empty if none)
 }
 
 func (ls *LetStatement) statementNode()       {}
@@ -98,8 +108,13 @@ func (es *ExpressionStatement) TokenLiteral() string {
 func (ls *LetStatement) String() string { // Returns the string representation of the let statement
 	var out bytes.Buffer
 	out.WriteString(ls.TokenLiteral() + " ") // Writes the token literal of the let statement
-	out.WriteString(ls.Name.String())        // Writes the name of the variable
-	out.WriteString(" = ")                   // Writes the assignment operator
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String()) // Writes the destructuring pattern
+	} else {
+		out.WriteString(ls.Name.String()) // Writes the name of the variable
+	}
+	out.WriteString(typeSuffix(": ", ls.Type)) // Writes the annotation, if any
+	out.WriteString(" = ")                     // Writes the assignment operator
 	if ls.Value != nil {
 		out.WriteString(ls.Value.String()) // Writes the value of the variable exa: let x = 5;
 	}
@@ -125,3 +140,167 @@ func (es *ExpressionStatement) String() string { // Returns the string represent
 
 // this is the string representation of the identifier
 func (i *Identifier) String() string { return i.Value }
+
+// The AST node for integer literals (e.g. 5, 1024)
+type IntegerLiteral struct {
+	Token token.Token // The token.INT token
+	Value int64       // The parsed value of the literal, when it fits in an int64
+	Big   *big.Int    // The parsed value when it does not fit in an int64 (e.g. 2 ** 64 written out), nil otherwise
+}
+
+func (il *IntegerLiteral) expressionNode()      {}
+func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
+func (il *IntegerLiteral) String() string       { return il.Token.Literal }
+
+// The AST node for the boolean keywords true and false
+type Boolean struct {
+	Token token.Token // The token.TRUE or token.FALSE token
+	Value bool        // The value of the keyword
+}
+
+func (b *Boolean) expressionNode()      {}
+func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
+func (b *Boolean) String() string       { return b.Token.Literal }
+
+// The AST node for string literals (e.g. "hello")
+type StringLiteral struct {
+	Token token.Token // The token.STRING token
+	Value string      // The contents of the string with escape sequences decoded
+}
+
+func (sl *StringLiteral) expressionNode()      {}
+func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StringLiteral) String() string       { return "\"" + escape(sl.Value) + "\"" }
+
+// escapeReplacer writes back the escape sequences the lexer decodes; a $ only needs one before a {
+var escapeReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\t", "\\t", "\r", "\\r", "${", "\\${")
+
+// escape returns the text of a string as it is written between the quotes, so that it reads back the same
+func escape(text string) string {
+	return escapeReplacer.Replace(text)
+}
+
+/*
+The AST node for strings with embedded expressions (e.g. "Hello ${name}!").
+
+The text around the expressions is kept in Literals, which always has one more
+element than Expressions: "a ${x} b ${y} c" has Literals ["a ", " b ", " c"]
+and Expressions [x, y].
+*/
+type InterpolatedString struct {
+	Token       token.Token  // The token.INTERP_HEAD token
+	Literals    []string     // The text before, between and after the expressions
+	Expressions []Expression // The embedded expressions, in source order
+}
+
+func (is *InterpolatedString) expressionNode()      {}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+
+// Returns the string with each expression written back inside ${} (e.g. "Hello ${name}!")
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+	out.WriteString("\"")
+	for i, lit := range is.Literals {
+		out.WriteString(escape(lit))
+		if i < len(is.Expressions) {
+			out.WriteString("${" + is.Expressions[i].String() + "}")
+		}
+	}
+	out.WriteString("\"")
+	return out.String()
+}
+
+// The AST node for prefix operators (e.g. ~x)
+type PrefixExpression struct {
+	Token    token.Token // The operator token (e.g. ~)
+	Operator string      // The operator literal
+	Right    Expression  // The operand
+}
+
+func (pe *PrefixExpression) expressionNode()      {}
+func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+
+// Returns the parenthesized form of the prefix expression (e.g. (~x))
+func (pe *PrefixExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(pe.Operator)
+	out.WriteString(pe.Right.String())
+	out.WriteString(")")
+	return out.String()
+}
+
+// The AST node for binary operators (e.g. a + b, a && b, a ** b)
+type InfixExpression struct {
+	Token    token.Token // The operator token (e.g. +, &&, **)
+	Left     Expression  // The left operand
+	Operator string      // The operator literal
+	Right    Expression  // The right operand
+}
+
+func (ie *InfixExpression) expressionNode()      {}
+func (ie *InfixExpression) TokenLiteral() string { return ie.Token.Literal }
+
+// Returns the fully parenthesized form of the infix expression (e.g. (a + (b * c)))
+func (ie *InfixExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	out.WriteString(" " + ie.Operator + " ")
+	out.WriteString(ie.Right.String())
+	out.WriteString(")")
+	return out.String()
+}
+
+// The AST node for the null keyword
+type NullLiteral struct {
+	Token token.Token // The token.NULL token
+}
+
+func (nl *NullLiteral) expressionNode()      {}
+func (nl *NullLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NullLiteral) String() string       { return nl.Token.Literal }
+
+// The AST node for index access (e.g. array[1], or the null-safe array?.[1])
+type IndexExpression struct {
+	Token    token.Token // The [ token, or the ?. token for null-safe access
+	Left     Expression  // The value being indexed
+	Index    Expression  // The index
+	Optional bool        // True for ?.[ which yields null instead of failing when Left is null
+}
+
+func (ie *IndexExpression) expressionNode()      {}
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+
+// Returns the parenthesized form of the index expression (e.g. (array[1]) or (array?.[1]))
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?.")
+	}
+	out.WriteString("[")
+	out.WriteString(ie.Index.String())
+	out.WriteString("])")
+	return out.String()
+}
+
+// The AST node for member access by name (e.g. p.x or config?.port)
+type MemberExpression struct {
+	Token    token.Token // The . or ?. token
+	Object   Expression  // The value whose member is read
+	Property *Identifier // The name of the member
+	Optional bool        // True for ?. which yields null instead of failing when Object is null
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+
+// Returns the member access as written (e.g. config?.port)
+func (me *MemberExpression) String() string {
+	if me.Optional {
+		return me.Object.String() + "?." + me.Property.String()
+	}
+	return me.Object.String() + "." + me.Property.String()
+}
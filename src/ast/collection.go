@@ -0,0 +1,80 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+The AST node for a spread (e.g. ...args), which stands for every element of Value in its place.
+It can appear among the arguments of a call and the elements of an array literal, where Value must
+be an array, and among the entries of a hash literal, where Value must be a hash.
+*/
+type SpreadElement struct {
+	Token token.Token // The token.ELLIPSIS token
+	Value Expression  // The array or hash whose elements are spread
+}
+
+func (se *SpreadElement) expressionNode()      {}
+func (se *SpreadElement) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadElement) String() string       { return "..." + se.Value.String() }
+
+// The AST node for array literals (e.g. [1, x, ...rest])
+type ArrayLiteral struct {
+	Token    token.Token  // The [ token
+	Elements []Expression // The elements in order, spreads as *SpreadElement
+}
+
+func (al *ArrayLiteral) expressionNode()      {}
+func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+
+// Returns the array as written (e.g. [1, (a + b), ...rest])
+func (al *ArrayLiteral) String() string {
+	elements := []string{}
+	for _, el := range al.Elements {
+		elements = append(elements, el.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// One entry of a hash literal: a key and its value, or a spread
+type HashPair struct {
+	Key   Expression // The key; an *Identifier key stands for the string of its name. Nil for a spread.
+	Value Expression // The value stored under Key, or the *SpreadElement when Key is nil
+}
+
+// Returns the entry as written, using the {name} shorthand when the value is the variable the key names
+func (hp *HashPair) String() string {
+	if hp.Key == nil {
+		return hp.Value.String()
+	}
+	if key, ok := hp.Key.(*Identifier); ok {
+		if value, ok := hp.Value.(*Identifier); ok && value.Value == key.Value {
+			return key.String()
+		}
+	}
+	return hp.Key.String() + ": " + hp.Value.String()
+}
+
+/*
+The AST node for hash literals (e.g. {...defaults, "port": 8080, name}). Entries are applied
+in order, so a key written after a spread overrides the value the spread gave it, and a
+later spread overrides the keys before it.
+*/
+type HashLiteral struct {
+	Token token.Token // The { token
+	Pairs []*HashPair // The entries, in source order
+}
+
+func (hl *HashLiteral) expressionNode()      {}
+func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+
+// Returns the hash as written (e.g. {...defaults, "port": 8080})
+func (hl *HashLiteral) String() string {
+	pairs := []string{}
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.String())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
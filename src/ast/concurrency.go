@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// The AST node for spawn expressions (e.g. spawn check(url)). It runs the call as a new task and evaluates to it.
+type SpawnExpression struct {
+	Token token.Token     // The token.SPAWN token
+	Call  *CallExpression // The call run by the task
+}
+
+func (se *SpawnExpression) expressionNode()      {}
+func (se *SpawnExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpawnExpression) String() string       { return se.TokenLiteral() + " " + se.Call.String() }
+
+// One case of a select: recv(ch) as v => body, send(ch, x) => body, or the default _ => body
+type SelectCase struct {
+	Token     token.Token     // The first token of the case
+	Operation *CallExpression // The recv or send call, nil for the default case
+	Binding   *Identifier     // The name the received value is bound to in Body, nil if it is not bound
+	Body      Expression      // The value of the select when this case is taken
+}
+
+// Returns the case as written (e.g. recv(ch) as v => v)
+func (sc *SelectCase) String() string {
+	out := "_"
+	if sc.Operation != nil {
+		out = sc.Operation.String()
+	}
+	if sc.Binding != nil {
+		out += " as " + sc.Binding.String()
+	}
+	return out + " => " + sc.Body.String()
+}
+
+/*
+The AST node for select expressions, which wait until one of several channel operations can go ahead:
+
+	select {
+		recv(results) as r => r,
+		send(jobs, next) => "queued",
+		_ => "busy",
+	}
+
+A ready case is taken at random. With a default case select does not wait.
+*/
+type SelectExpression struct {
+	Token token.Token   // The select token
+	Cases []*SelectCase // The cases in source order, the default case (if any) among them
+}
+
+func (se *SelectExpression) expressionNode()      {}
+func (se *SelectExpression) TokenLiteral() string { return se.Token.Literal }
+
+// Returns the select expression on one line
+func (se *SelectExpression) String() string {
+	cases := []string{}
+	for _, c := range se.Cases {
+		cases = append(cases, c.String())
+	}
+	return se.TokenLiteral() + " { " + strings.Join(cases, ", ") + " }"
+}
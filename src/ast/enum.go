@@ -0,0 +1,72 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// One variant of an enum (e.g. Ok(v) or Pending)
+type EnumVariant struct {
+	Enum   *Identifier   // The enum the variant belongs to
+	Name   *Identifier   // The name of the variant, which is also its constructor
+	Fields []*Identifier // The names of the values the variant carries, empty for a bare variant
+}
+
+// Returns the variant as written (e.g. Ok(v) or Pending)
+func (ev *EnumVariant) String() string {
+	if len(ev.Fields) == 0 {
+		return ev.Name.String()
+	}
+	fields := []string{}
+	for _, f := range ev.Fields {
+		fields = append(fields, f.String())
+	}
+	return ev.Name.String() + "(" + strings.Join(fields, ", ") + ")"
+}
+
+// The AST node for enum declarations (e.g. enum Result { Ok(v), Err(e) })
+type EnumDecl struct {
+	Token    token.Token    // The token.ENUM token
+	Name     *Identifier    // The name of the enum
+	Variants []*EnumVariant // The variants, in declaration order
+}
+
+func (ed *EnumDecl) statementNode()       {}
+func (ed *EnumDecl) TokenLiteral() string { return ed.Token.Literal }
+
+// Returns the declaration as written (e.g. enum Result { Ok(v), Err(e) })
+func (ed *EnumDecl) String() string {
+	variants := []string{}
+	for _, v := range ed.Variants {
+		variants = append(variants, v.String())
+	}
+	return ed.TokenLiteral() + " " + ed.Name.String() + " { " + strings.Join(variants, ", ") + " }"
+}
+
+// Matches an enum variant and destructures the values it carries (e.g. Ok(v), Pending or Result.Err(_))
+type VariantPattern struct {
+	Token     token.Token // The first token of the pattern
+	Enum      *Identifier // The enum qualifier in Result.Ok(v), nil if the variant was not qualified
+	Name      *Identifier // The name of the variant
+	Arguments []Pattern   // The patterns for the carried values, empty for a bare variant
+}
+
+func (vp *VariantPattern) patternNode()         {}
+func (vp *VariantPattern) TokenLiteral() string { return vp.Token.Literal }
+
+// Returns the pattern as written (e.g. Result.Ok(v))
+func (vp *VariantPattern) String() string {
+	out := vp.Name.String()
+	if vp.Enum != nil {
+		out = vp.Enum.String() + "." + out
+	}
+	if len(vp.Arguments) == 0 {
+		return out
+	}
+	args := []string{}
+	for _, a := range vp.Arguments {
+		args = append(args, a.String())
+	}
+	return out + "(" + strings.Join(args, ", ") + ")"
+}
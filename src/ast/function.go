@@ -0,0 +1,262 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// The AST node for a list of statements between braces (e.g. the body of a function)
+type BlockStatement struct {
+	Token      token.Token // The { token
+	Statements []Statement // The statements in the block
+}
+
+func (bs *BlockStatement) statementNode()       {}
+func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+
+// Returns the statements of the block between braces (e.g. { return x; })
+func (bs *BlockStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("{ ")
+	for _, s := range bs.Statements {
+		out.WriteString(s.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+	return out.String()
+}
+
+// One parameter of a function literal: x, y: int = 10 or ...rest
+type Parameter struct {
+	Name     *Identifier    // The name of the parameter
+	Type     TypeAnnotation // The type written after the name, nil if there is none
+	Default  Expression     // The value used when no argument is passed, nil if the parameter is required
+	Variadic bool           // True for ...rest which collects the remaining arguments into an array
+}
+
+// Returns the parameter as written (e.g. y: int = 10)
+func (pm *Parameter) String() string {
+	out := pm.Name.String() + typeSuffix(": ", pm.Type)
+	switch {
+	case pm.Variadic:
+		return "..." + out
+	case pm.Default != nil:
+		return out + " = " + pm.Default.String()
+	default:
+		return out
+	}
+}
+
+/*
+The AST node for function literals (e.g. fn(x, y = 10, ...rest) { x + y; }).
+
+Parameters are ordered: required ones first, then ones with defaults, then at
+most one variadic parameter. Defaults are expressions, so the evaluator fills
+them in only when the argument is missing, in the callee's scope.
+
+A function whose body yields is a generator: calling it runs nothing yet and
+returns an iterator whose next() runs the body up to the following yield.
+*/
+type FunctionLiteral struct {
+	Token      token.Token     // The token.FUNCTION token
+	Name       string          // The name the function was bound to with let, empty if anonymous
+	Parameters []*Parameter    // The parameters of the function
+	ReturnType TypeAnnotation  // The type written after ->, nil if there is none
+	Body       *BlockStatement // The body of the function
+	Generator  bool            // True if the body contains a yield, so calling the function returns an iterator
+}
+
+func (fl *FunctionLiteral) expressionNode()      {}
+func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+
+// Returns the function literal as written (e.g. fn(x, y = 10) { (x + y) } or fn(x: int) -> int { x })
+func (fl *FunctionLiteral) String() string {
+	params := []string{}
+	for _, p := range fl.Parameters {
+		params = append(params, p.String())
+	}
+	return fl.TokenLiteral() + "(" + strings.Join(params, ", ") + ")" + typeSuffix(" -> ", fl.ReturnType) + " " + fl.Body.String()
+}
+
+/*
+Arity returns how many arguments the function accepts.
+
+@return min int - The number of required parameters
+
+@return max int - The total number of parameters, or -1 if the last one is variadic
+*/
+func (fl *FunctionLiteral) Arity() (min, max int) {
+	for _, p := range fl.Parameters {
+		switch {
+		case p.Variadic:
+			return min, -1
+		case p.Default == nil:
+			min++
+		}
+		max++
+	}
+	return min, max
+}
+
+/*
+CheckArguments checks the arguments of a call against the parameters of the function:
+the number of arguments, and that every named argument names a parameter that was not
+already given positionally. A spread (f(...xs)) passes a number of arguments that is only
+known when the program runs, so with one only the named arguments are checked.
+
+@param args []Expression - The arguments of the call, named ones as *NamedArgument
+
+@return error - A message such as "f expects 1-2 arguments, got 3", nil if the call is valid
+*/
+func (fl *FunctionLiteral) CheckArguments(args []Expression) error {
+	name := fl.Name
+	if name == "" {
+		name = "function"
+	}
+
+	spread := false
+	for _, arg := range args {
+		if _, ok := arg.(*SpreadElement); ok {
+			spread = true
+		}
+	}
+
+	min, max := fl.Arity()
+	if !spread && (len(args) < min || (max >= 0 && len(args) > max)) {
+		return fmt.Errorf("%s expects %s, got %d", name, describeArity(min, max), len(args))
+	}
+
+	given := map[string]bool{} // Parameters that already have an argument
+	for i, arg := range args {
+		named, ok := arg.(*NamedArgument)
+		if !ok {
+			if !spread && i < len(fl.Parameters) && !fl.Parameters[i].Variadic {
+				given[fl.Parameters[i].Name.Value] = true
+			}
+			continue
+		}
+
+		param := fl.parameter(named.Name.Value)
+		switch {
+		case param == nil:
+			return fmt.Errorf("%s has no parameter named %s", name, named.Name.Value)
+		case param.Variadic:
+			return fmt.Errorf("%s cannot take its variadic parameter %s by name", name, named.Name.Value)
+		case given[named.Name.Value]:
+			return fmt.Errorf("%s got more than one value for parameter %s", name, named.Name.Value)
+		}
+		given[named.Name.Value] = true
+	}
+
+	for _, p := range fl.Parameters { // Named arguments can leave a required parameter out
+		if !spread && p.Default == nil && !p.Variadic && !given[p.Name.Value] {
+			return fmt.Errorf("%s is missing an argument for parameter %s", name, p.Name.Value)
+		}
+	}
+	return nil
+}
+
+// parameter returns the parameter with the given name, nil if there is none
+func (fl *FunctionLiteral) parameter(name string) *Parameter {
+	for _, p := range fl.Parameters {
+		if p.Name.Value == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// describeArity turns the result of Arity into words (e.g. "1-2 arguments", "at least 1 argument")
+func describeArity(min, max int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+
+	switch {
+	case max < 0:
+		return "at least " + plural(min)
+	case min == max:
+		return plural(min)
+	default:
+		return fmt.Sprintf("%d-%d arguments", min, max)
+	}
+}
+
+// The AST node for yield expressions (e.g. yield x). It evaluates to the value passed to the next call of next().
+type YieldExpression struct {
+	Token token.Token // The token.YIELD token
+	Value Expression  // The value produced, nil for a bare yield
+}
+
+func (ye *YieldExpression) expressionNode()      {}
+func (ye *YieldExpression) TokenLiteral() string { return ye.Token.Literal }
+
+// Returns the yield as written (e.g. yield x)
+func (ye *YieldExpression) String() string {
+	if ye.Value == nil {
+		return ye.TokenLiteral()
+	}
+	return ye.TokenLiteral() + " " + ye.Value.String()
+}
+
+/*
+The AST node for macro literals (e.g. macro(a, b) { quote(unquote(b) - unquote(a)); }).
+
+Macros bound with let at the top level of a program are expanded before the program
+runs: every call to one is replaced by the AST its body produces from the unevaluated
+argument expressions.
+*/
+type MacroLiteral struct {
+	Token      token.Token     // The token.MACRO token
+	Parameters []*Identifier   // The parameters, bound to the argument expressions
+	Body       *BlockStatement // The body of the macro
+}
+
+func (ml *MacroLiteral) expressionNode()      {}
+func (ml *MacroLiteral) TokenLiteral() string { return ml.Token.Literal }
+
+// Returns the macro literal as written (e.g. macro(a, b) { quote(a) })
+func (ml *MacroLiteral) String() string {
+	params := []string{}
+	for _, p := range ml.Parameters {
+		params = append(params, p.String())
+	}
+	return ml.TokenLiteral() + "(" + strings.Join(params, ", ") + ") " + ml.Body.String()
+}
+
+// The AST node for function calls (e.g. add(1, 2) or add(y: 2, x: 1))
+type CallExpression struct {
+	Token     token.Token  // The ( token
+	Function  Expression   // The function being called (an identifier or a function literal)
+	Arguments []Expression // The arguments, named ones as *NamedArgument after the positional ones
+	Tail      bool         // True if the call is the last thing its function does (see MarkTailCalls)
+}
+
+func (ce *CallExpression) expressionNode()      {}
+func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+
+// Returns the call as written (e.g. add(1, y: 2))
+func (ce *CallExpression) String() string {
+	args := []string{}
+	for _, a := range ce.Arguments {
+		args = append(args, a.String())
+	}
+	return ce.Function.String() + "(" + strings.Join(args, ", ") + ")"
+}
+
+// The AST node for an argument passed by parameter name (e.g. the y: 2 in f(y: 2))
+type NamedArgument struct {
+	Token token.Token // The token.IDENT token of the name
+	Name  *Identifier // The name of the parameter
+	Value Expression  // The value passed for it
+}
+
+func (na *NamedArgument) expressionNode()      {}
+func (na *NamedArgument) TokenLiteral() string { return na.Token.Literal }
+func (na *NamedArgument) String() string       { return na.Name.String() + ": " + na.Value.String() }
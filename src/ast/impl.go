@@ -0,0 +1,75 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// A method inside a trait or impl block (e.g. fn len(self) { ... })
+type MethodDecl struct {
+	Token      token.Token     // The token.FUNCTION token
+	Name       *Identifier     // The name of the method
+	Parameters []*Parameter    // The parameters, usually starting with self
+	ReturnType TypeAnnotation  // The type written after ->, nil if there is none
+	Body       *BlockStatement // The body, nil for a trait method without a default
+	Generator  bool            // True if the body contains a yield
+}
+
+// Returns the method as written (e.g. fn area(self) or fn len(self) { ... })
+func (md *MethodDecl) String() string {
+	params := []string{}
+	for _, p := range md.Parameters {
+		params = append(params, p.String())
+	}
+	out := md.Token.Literal + " " + md.Name.String() + "(" + strings.Join(params, ", ") + ")" + typeSuffix(" -> ", md.ReturnType)
+	if md.Body != nil {
+		out += " " + md.Body.String()
+	}
+	return out
+}
+
+// The AST node for trait declarations (e.g. trait Shape { fn area(self) })
+type TraitDecl struct {
+	Token   token.Token   // The token.TRAIT token
+	Name    *Identifier   // The name of the trait
+	Methods []*MethodDecl // The methods an impl must provide, or may override if they have a body
+}
+
+func (td *TraitDecl) statementNode()       {}
+func (td *TraitDecl) TokenLiteral() string { return td.Token.Literal }
+func (td *TraitDecl) String() string {
+	return td.TokenLiteral() + " " + td.Name.String() + " " + methodsString(td.Methods)
+}
+
+/*
+The AST node for impl blocks, which attach methods to a type.
+
+impl Point { ... } adds methods to Point directly, while impl Shape for Point { ... }
+also declares that Point satisfies the trait Shape.
+*/
+type ImplDecl struct {
+	Token   token.Token   // The token.IMPL token
+	Trait   *Identifier   // The trait being implemented, nil for an inherent impl
+	Type    *Identifier   // The type the methods belong to
+	Methods []*MethodDecl // The methods, in source order
+}
+
+func (id *ImplDecl) statementNode()       {}
+func (id *ImplDecl) TokenLiteral() string { return id.Token.Literal }
+func (id *ImplDecl) String() string {
+	out := id.TokenLiteral() + " "
+	if id.Trait != nil {
+		out += id.Trait.String() + " for "
+	}
+	return out + id.Type.String() + " " + methodsString(id.Methods)
+}
+
+// methodsString returns the methods of a trait or impl between braces
+func methodsString(methods []*MethodDecl) string {
+	parts := []string{}
+	for _, m := range methods {
+		parts = append(parts, m.String())
+	}
+	return "{ " + strings.Join(parts, " ") + " }"
+}
@@ -0,0 +1,203 @@
+package ast
+
+// ModifierFunc is called by Modify on every node, and the node is replaced by what it returns
+type ModifierFunc func(Node) Node
+
+/*
+Modify walks the tree rooted at node depth first, children before parents, and replaces every
+node by the result of calling modifier on it. The tree is changed in place.
+
+A replacement must be usable where the original node was (an Expression for an expression,
+a Pattern for a pattern, ...); one that is not is dropped and leaves nil behind. Names that
+are not values in their own right (e.g. let names, parameter names, member names) are not visited.
+
+@param node Node - The root of the tree to modify
+
+@param modifier ModifierFunc - Called on every node, returning the node to put in its place
+
+@return Node - The result of calling modifier on the root
+*/
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch node := node.(type) {
+
+	// Statements
+	case *Program:
+		node.Statements = modifyStatements(node.Statements, modifier)
+	case *ExpressionStatement:
+		node.Expression = modifyExpression(node.Expression, modifier)
+	case *LetStatement:
+		if node.Pattern != nil {
+			node.Pattern = modifyPattern(node.Pattern, modifier)
+		}
+		node.Value = modifyExpression(node.Value, modifier)
+	case *ReturnStatement:
+		node.ReturnValue = modifyExpression(node.ReturnValue, modifier)
+	case *BlockStatement:
+		node.Statements = modifyStatements(node.Statements, modifier)
+	case *ThrowStatement:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *ExportStatement:
+		node.Declaration, _ = Modify(node.Declaration, modifier).(Statement)
+	case *TraitDecl:
+		modifyMethods(node.Methods, modifier)
+	case *ImplDecl:
+		modifyMethods(node.Methods, modifier)
+	case *StructDecl, *EnumDecl, *ImportStatement:
+		// Only names, nothing to walk into
+
+	// Expressions
+	case *InterpolatedString:
+		for i, exp := range node.Expressions {
+			node.Expressions[i] = modifyExpression(exp, modifier)
+		}
+	case *PrefixExpression:
+		node.Right = modifyExpression(node.Right, modifier)
+	case *InfixExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Right = modifyExpression(node.Right, modifier)
+	case *IndexExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Index = modifyExpression(node.Index, modifier)
+	case *SliceExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Start = modifyExpression(node.Start, modifier)
+		node.End = modifyExpression(node.End, modifier)
+		node.Step = modifyExpression(node.Step, modifier)
+	case *RangeExpression:
+		node.Start = modifyExpression(node.Start, modifier)
+		node.End = modifyExpression(node.End, modifier)
+	case *MemberExpression:
+		node.Object = modifyExpression(node.Object, modifier)
+	case *FunctionLiteral:
+		modifyParameters(node.Parameters, modifier)
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+	case *ArrowFunction:
+		modifyParameters(node.Parameters, modifier)
+		node.Body = modifyExpression(node.Body, modifier)
+	case *PipelineExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Right = modifyExpression(node.Right, modifier)
+	case *MacroLiteral:
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+	case *CallExpression:
+		node.Function = modifyExpression(node.Function, modifier)
+		for i, arg := range node.Arguments {
+			node.Arguments[i] = modifyExpression(arg, modifier)
+		}
+	case *YieldExpression:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *SpawnExpression:
+		node.Call, _ = Modify(node.Call, modifier).(*CallExpression)
+	case *SelectExpression:
+		for _, c := range node.Cases {
+			if c.Operation != nil {
+				c.Operation, _ = Modify(c.Operation, modifier).(*CallExpression)
+			}
+			c.Body = modifyExpression(c.Body, modifier)
+		}
+	case *NamedArgument:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *SpreadElement:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *ArrayLiteral:
+		for i, el := range node.Elements {
+			node.Elements[i] = modifyExpression(el, modifier)
+		}
+	case *HashLiteral:
+		for _, pair := range node.Pairs {
+			pair.Key = modifyExpression(pair.Key, modifier)
+			pair.Value = modifyExpression(pair.Value, modifier)
+		}
+	case *MatchExpression:
+		node.Subject = modifyExpression(node.Subject, modifier)
+		for _, arm := range node.Arms {
+			arm.Pattern = modifyPattern(arm.Pattern, modifier)
+			arm.Guard = modifyExpression(arm.Guard, modifier)
+			arm.Body = modifyExpression(arm.Body, modifier)
+		}
+	case *StructLiteral:
+		for _, field := range node.Fields {
+			field.Value = modifyExpression(field.Value, modifier)
+		}
+	case *AssignExpression:
+		node.Target, _ = Modify(node.Target, modifier).(*MemberExpression)
+		node.Value = modifyExpression(node.Value, modifier)
+	case *TryExpression:
+		node.Block, _ = Modify(node.Block, modifier).(*BlockStatement)
+		if node.Catch != nil {
+			node.Catch, _ = Modify(node.Catch, modifier).(*BlockStatement)
+		}
+		if node.Finally != nil {
+			node.Finally, _ = Modify(node.Finally, modifier).(*BlockStatement)
+		}
+	case *Identifier, *IntegerLiteral, *StringLiteral, *Boolean, *NullLiteral:
+		// Leaves
+
+	// Patterns
+	case *LiteralPattern:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *ArrayPattern:
+		for i, el := range node.Elements {
+			node.Elements[i] = modifyPattern(el, modifier)
+		}
+	case *HashPattern:
+		for _, pair := range node.Pairs {
+			pair.Key = modifyExpression(pair.Key, modifier)
+			pair.Value = modifyPattern(pair.Value, modifier)
+		}
+	case *VariantPattern:
+		for i, arg := range node.Arguments {
+			node.Arguments[i] = modifyPattern(arg, modifier)
+		}
+	case *BindingPattern, *WildcardPattern:
+		// Leaves
+	}
+
+	return modifier(node)
+}
+
+// modifyStatements modifies every statement of a list, dropping the ones replaced by a non-statement
+func modifyStatements(statements []Statement, modifier ModifierFunc) []Statement {
+	modified := statements[:0]
+	for _, stmt := range statements {
+		if stmt, ok := Modify(stmt, modifier).(Statement); ok {
+			modified = append(modified, stmt)
+		}
+	}
+	return modified
+}
+
+// modifyExpression modifies an expression that may be missing (e.g. a bare return's value)
+func modifyExpression(exp Expression, modifier ModifierFunc) Expression {
+	if exp == nil {
+		return nil
+	}
+	modified, _ := Modify(exp, modifier).(Expression)
+	return modified
+}
+
+// modifyPattern modifies a pattern
+func modifyPattern(pattern Pattern, modifier ModifierFunc) Pattern {
+	if pattern == nil {
+		return nil
+	}
+	modified, _ := Modify(pattern, modifier).(Pattern)
+	return modified
+}
+
+// modifyParameters modifies the default values of parameters
+func modifyParameters(params []*Parameter, modifier ModifierFunc) {
+	for _, param := range params {
+		param.Default = modifyExpression(param.Default, modifier)
+	}
+}
+
+// modifyMethods modifies the parameter defaults and bodies of trait or impl methods
+func modifyMethods(methods []*MethodDecl, modifier ModifierFunc) {
+	for _, method := range methods {
+		modifyParameters(method.Parameters, modifier)
+		if method.Body != nil {
+			method.Body, _ = Modify(method.Body, modifier).(*BlockStatement)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ast
+
+import (
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// The AST node for the import statement (e.g. import "./utils.bro" as utils;)
+type ImportStatement struct {
+	Token token.Token    // The token.IMPORT token
+	Path  *StringLiteral // The path of the module, relative to the importing file or found on BROPATH
+	Alias *Identifier    // The name the module's exports are reachable under
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) String() string {
+	return is.TokenLiteral() + " " + is.Path.String() + " as " + is.Alias.String() + ";"
+}
+
+// The AST node for the export statement (e.g. export let x = 5;)
+type ExportStatement struct {
+	Token       token.Token // The token.EXPORT token
+	Declaration Statement   // The let, struct, enum or trait declaration being exported
+}
+
+func (es *ExportStatement) statementNode()       {}
+func (es *ExportStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Declaration.String()
+}
+
+/*
+Names returns the names the exported declaration binds: the variable of a let
+(every name of a destructuring let), or the name of a struct, enum or trait.
+
+@return []string - The exported names, in source order
+*/
+func (es *ExportStatement) Names() []string {
+	switch decl := es.Declaration.(type) {
+	case *LetStatement:
+		if decl.Pattern != nil {
+			return PatternNames(decl.Pattern)
+		}
+		return []string{decl.Name.Value}
+	case *StructDecl:
+		return []string{decl.Name.Value}
+	case *EnumDecl:
+		return []string{decl.Name.Value}
+	case *TraitDecl:
+		return []string{decl.Name.Value}
+	}
+	return nil
+}
+
+/*
+PatternNames returns every name a pattern binds when it matches, in source order
+
+@param pattern Pattern - The pattern to collect the names of
+
+@return []string - The bound names (the _ wildcard binds nothing)
+*/
+func PatternNames(pattern Pattern) []string {
+	names := []string{}
+	switch pat := pattern.(type) {
+	case *BindingPattern:
+		names = append(names, pat.Name.Value)
+	case *ArrayPattern:
+		for _, el := range pat.Elements {
+			names = append(names, PatternNames(el)...)
+		}
+		if pat.Rest != nil {
+			names = append(names, pat.Rest.Value)
+		}
+	case *HashPattern:
+		for _, pair := range pat.Pairs {
+			names = append(names, PatternNames(pair.Value)...)
+		}
+		if pat.Rest != nil {
+			names = append(names, pat.Rest.Value)
+		}
+	case *VariantPattern:
+		for _, arg := range pat.Arguments {
+			names = append(names, PatternNames(arg)...)
+		}
+	}
+	return names
+}
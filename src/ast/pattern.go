@@ -0,0 +1,143 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// The Pattern interface is implemented by the nodes that can appear on the left of a match arm
+// or after let. A pattern either matches a value (binding any names it introduces) or it does not.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// Matches a value equal to a literal (e.g. 0, "zero", true, null)
+type LiteralPattern struct {
+	Token token.Token // The first token of the literal
+	Value Expression  // The literal itself
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Token.Literal }
+func (lp *LiteralPattern) String() string       { return lp.Value.String() }
+
+// Matches any value and binds it to a name (e.g. x)
+type BindingPattern struct {
+	Token token.Token // The token.IDENT token
+	Name  *Identifier // The name the value is bound to
+}
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Token.Literal }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+// Matches any value without binding it (_)
+type WildcardPattern struct {
+	Token token.Token // The _ token
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) String() string       { return "_" }
+
+// Matches an array element by element (e.g. [a, b] or [first, ...rest])
+type ArrayPattern struct {
+	Token    token.Token // The [ token
+	Elements []Pattern   // The patterns for the leading elements
+	Rest     *Identifier // The name bound to the remaining elements after ..., nil if there is no rest
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+
+// Returns the array pattern as written (e.g. [a, b, ...rest])
+func (ap *ArrayPattern) String() string {
+	parts := []string{}
+	for _, el := range ap.Elements {
+		parts = append(parts, el.String())
+	}
+	if ap.Rest != nil {
+		parts = append(parts, "..."+ap.Rest.String())
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// One key of a hash pattern and the pattern its value must match
+type HashPatternPair struct {
+	Key   Expression // The key to look up; an *Identifier key stands for the string of its name
+	Value Pattern    // The pattern for the value stored under Key
+}
+
+// Returns the pair as written, using the {name} shorthand when the key binds a variable of the same name
+func (hpp *HashPatternPair) String() string {
+	if key, ok := hpp.Key.(*Identifier); ok {
+		if binding, ok := hpp.Value.(*BindingPattern); ok && binding.Name.Value == key.Value {
+			return key.String()
+		}
+	}
+	return hpp.Key.String() + ": " + hpp.Value.String()
+}
+
+// Matches a hash that has at least the listed keys (e.g. {"type": "x", ...rest})
+type HashPattern struct {
+	Token token.Token        // The { token
+	Pairs []*HashPatternPair // The keys to match, in source order
+	Rest  *Identifier        // The name bound to a hash of the unlisted keys after ..., nil if there is no rest
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+
+// Returns the hash pattern as written (e.g. {"type": "x", ...rest})
+func (hp *HashPattern) String() string {
+	parts := []string{}
+	for _, pair := range hp.Pairs {
+		parts = append(parts, pair.String())
+	}
+	if hp.Rest != nil {
+		parts = append(parts, "..."+hp.Rest.String())
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// One arm of a match expression: pattern [if guard] => body
+type MatchArm struct {
+	Token   token.Token // The first token of the pattern
+	Pattern Pattern     // The pattern the subject must match
+	Guard   Expression  // The condition after if that must also hold, nil if there is no guard
+	Body    Expression  // The value of the match when this arm is taken
+}
+
+// Returns the arm as written (e.g. [a, b] if a > b => a)
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if " + ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+	return out.String()
+}
+
+// The AST node for match expressions (e.g. match (x) { 0 => "zero", _ => "other" })
+type MatchExpression struct {
+	Token   token.Token // The token.MATCH token
+	Subject Expression  // The value being matched
+	Arms    []*MatchArm // The arms, tried in source order
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+
+// Returns the match expression on one line (e.g. match (x) { 0 => "zero", _ => "other" })
+func (me *MatchExpression) String() string {
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+	return "match (" + me.Subject.String() + ") { " + strings.Join(arms, ", ") + " }"
+}
@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+The AST node for slice expressions (e.g. arr[1:3], arr[:-1], str[::2], arr[1:10:2]). Every part is optional
+and the slice is of the same kind as Left: slicing an array gives an array, slicing a string gives a string.
+
+Bounds follow Python: a negative bound counts from the end, and bounds out of range are clamped instead of
+failing, so arr[-100:100] is the whole of arr and arr[5:2] is empty. A missing Start or End means the edge
+the step walks away from or towards. A step of 0 is an error; a negative step walks backwards (arr[::-1]).
+*/
+type SliceExpression struct {
+	Token    token.Token // The [ token, or the ?. token for null-safe access
+	Left     Expression  // The array or string being sliced
+	Start    Expression  // The first index, nil if it is left out
+	End      Expression  // The index the slice stops before, nil if it is left out
+	Step     Expression  // The distance between indexes, nil if it is left out (a step of 1)
+	Optional bool        // True for ?.[ which yields null instead of failing when Left is null
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+
+// Returns the parenthesized form of the slice with the missing parts left empty (e.g. (arr[1:]) or (arr[::2]))
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	if se.Optional {
+		out.WriteString("?.")
+	}
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	if se.Step != nil {
+		out.WriteString(":")
+		out.WriteString(se.Step.String())
+	}
+	out.WriteString("])")
+	return out.String()
+}
+
+/*
+The AST node for range expressions (e.g. 0..10). A range holds the ints from Start up to, but not
+including, End. Calling step on it (e.g. (0..10).step(2)) gives a range that only holds every n-th of them.
+*/
+type RangeExpression struct {
+	Token token.Token // The token.DOT_DOT token
+	Start Expression  // The first int of the range
+	End   Expression  // The int the range stops before
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	return "(" + re.Start.String() + ".." + re.End.String() + ")"
+}
@@ -0,0 +1,76 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// The AST node for struct declarations (e.g. struct Point { x, y })
+type StructDecl struct {
+	Token  token.Token   // The token.STRUCT token
+	Name   *Identifier   // The name of the type
+	Fields []*Identifier // The field names, in declaration order
+}
+
+func (sd *StructDecl) statementNode()       {}
+func (sd *StructDecl) TokenLiteral() string { return sd.Token.Literal }
+
+// Returns the declaration as written (e.g. struct Point { x, y })
+func (sd *StructDecl) String() string {
+	fields := []string{}
+	for _, f := range sd.Fields {
+		fields = append(fields, f.String())
+	}
+	return sd.TokenLiteral() + " " + sd.Name.String() + " { " + strings.Join(fields, ", ") + " }"
+}
+
+// HasField reports whether the struct declares a field with the name
+func (sd *StructDecl) HasField(name string) bool {
+	for _, f := range sd.Fields {
+		if f.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// One field: value entry of a struct literal
+type StructField struct {
+	Name  *Identifier // The field being set
+	Value Expression  // The value of the field
+}
+
+// The AST node for constructing a struct (e.g. Point{x: 1, y: 2})
+type StructLiteral struct {
+	Token  token.Token    // The { token
+	Type   *Identifier    // The name of the struct type
+	Fields []*StructField // The fields being set, in source order
+}
+
+func (sl *StructLiteral) expressionNode()      {}
+func (sl *StructLiteral) TokenLiteral() string { return sl.Token.Literal }
+
+// Returns the literal in the same form struct values print in (e.g. Point{x: 1, y: 2})
+func (sl *StructLiteral) String() string {
+	fields := []string{}
+	for _, f := range sl.Fields {
+		fields = append(fields, f.Name.String()+": "+f.Value.String())
+	}
+	return sl.Type.String() + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// The AST node for updating a field (e.g. p.x = 3)
+type AssignExpression struct {
+	Token  token.Token       // The = token
+	Target *MemberExpression // The field being updated
+	Value  Expression        // The new value
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+
+// Returns the parenthesized form of the assignment (e.g. (p.x = 3))
+func (ae *AssignExpression) String() string {
+	return "(" + ae.Target.String() + " = " + ae.Value.String() + ")"
+}
@@ -0,0 +1,40 @@
+package ast
+
+/*
+MarkTailCalls sets Tail on every call in tail position in the body of a function: the
+value of a return statement, the last expression of the body, and the arms of a match
+in tail position. The caller's frame is not needed after a tail call, so an evaluator
+can run it with a trampoline instead of growing the Go stack.
+
+Calls inside try, catch and finally blocks are never tail calls, because the handlers
+(or the finally block) still have to run when the call returns. Nested function literals
+are left alone; they are marked when their own bodies are parsed.
+
+@param body *BlockStatement - The body of a function or method
+*/
+func MarkTailCalls(body *BlockStatement) {
+	for i, stmt := range body.Statements {
+		switch stmt := stmt.(type) {
+		case *ReturnStatement:
+			markTailCall(stmt.ReturnValue)
+		case *ExpressionStatement:
+			if i == len(body.Statements)-1 { // The value of the last expression is the function's result
+				markTailCall(stmt.Expression)
+			}
+		}
+	}
+}
+
+// markTailCall marks exp if it is a call or a pipeline, or the calls it returns directly if it is a match
+func markTailCall(exp Expression) {
+	switch exp := exp.(type) {
+	case *CallExpression:
+		exp.Tail = true
+	case *PipelineExpression:
+		exp.Tail = true
+	case *MatchExpression:
+		for _, arm := range exp.Arms {
+			markTailCall(arm.Body)
+		}
+	}
+}
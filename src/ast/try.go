@@ -0,0 +1,53 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// The AST node for the throw statement (e.g. throw "not found";)
+type ThrowStatement struct {
+	Token token.Token // The token.THROW token, whose position is where the error was raised
+	Value Expression  // The value being thrown, which can be of any type
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
+
+/*
+The AST node for try expressions (e.g. try { risky(); } catch (e) { 0; } finally { cleanup(); }).
+
+At least one of Catch and Finally is set. The value of the expression is the value of
+Block, or of Catch if something was thrown; Finally runs either way and its value is dropped.
+*/
+type TryExpression struct {
+	Token      token.Token     // The token.TRY token
+	Block      *BlockStatement // The statements that may throw
+	CatchParam *Identifier     // The name the thrown value is bound to in Catch, nil if the catch has no (e)
+	Catch      *BlockStatement // The statements run when Block throws, nil if there is no catch
+	Finally    *BlockStatement // The statements run after Block and Catch, nil if there is no finally
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+
+// Returns the try expression as written
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try " + te.Block.String())
+	if te.Catch != nil {
+		out.WriteString(" catch ")
+		if te.CatchParam != nil {
+			out.WriteString("(" + te.CatchParam.String() + ") ")
+		}
+		out.WriteString(te.Catch.String())
+	}
+	if te.Finally != nil {
+		out.WriteString(" finally " + te.Finally.String())
+	}
+	return out.String()
+}
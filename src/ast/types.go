@@ -0,0 +1,51 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// The TypeAnnotation interface is implemented by the nodes written after : or -> (e.g. int or fn(int) -> bool).
+// Annotations are only read by the type checker; the evaluator ignores them.
+type TypeAnnotation interface {
+	Node
+	typeNode()
+}
+
+// A type written as a name (e.g. int, string, bool, null, any, or a struct, enum or trait name)
+type NamedType struct {
+	Token token.Token // The token of the name
+	Name  string      // The name of the type
+}
+
+func (nt *NamedType) typeNode()            {}
+func (nt *NamedType) TokenLiteral() string { return nt.Token.Literal }
+func (nt *NamedType) String() string       { return nt.Name }
+
+// A function type (e.g. fn(int, string) -> bool)
+type FunctionType struct {
+	Token      token.Token      // The token.FUNCTION token
+	Parameters []TypeAnnotation // The parameter types
+	Return     TypeAnnotation   // The return type
+}
+
+func (ft *FunctionType) typeNode()            {}
+func (ft *FunctionType) TokenLiteral() string { return ft.Token.Literal }
+
+// Returns the function type as written (e.g. fn(int, string) -> bool)
+func (ft *FunctionType) String() string {
+	params := []string{}
+	for _, p := range ft.Parameters {
+		params = append(params, p.String())
+	}
+	return ft.TokenLiteral() + "(" + strings.Join(params, ", ") + ") -> " + ft.Return.String()
+}
+
+// typeSuffix returns the annotation as written after a name (e.g. ": int"), empty if there is none
+func typeSuffix(sep string, t TypeAnnotation) string {
+	if t == nil {
+		return ""
+	}
+	return sep + t.String()
+}
@@ -0,0 +1,769 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// checker walks a program and records the type errors it finds
+type checker struct {
+	errors   []error
+	types    map[string]Type            // The names usable in annotations: the builtins and the declared structs, enums and traits
+	impls    map[string]map[string]bool // The traits each type implements, by type name
+	structs  map[string]*ast.StructDecl // The declared structs, by name
+	scope    *scope                     // The innermost scope
+	returns  []Type                     // The return type of each function being checked, innermost last
+	returned []bool                     // Whether each function being checked has a return statement, like returns
+}
+
+// scope maps the names visible in a block to their types
+type scope struct {
+	vars     map[string]Type
+	literals map[string]*ast.FunctionLiteral // The function literals names are bound to, so calls can be checked against their parameters
+	outer    *scope
+}
+
+/*
+Check type checks a program before it runs. Annotated lets, parameters and return
+types are checked against the values given to them, and operators and calls against
+their operands wherever the types are known. Unannotated parameters have type any,
+so unannotated code is accepted as it is.
+
+@param program *ast.Program - The parsed program
+
+@return []error - The type errors, each ending with the line:column it was found at (empty if the program checks)
+*/
+func Check(program *ast.Program) []error {
+	c := &checker{
+		errors:  []error{},
+		types:   map[string]Type{},
+		impls:   map[string]map[string]bool{},
+		structs: map[string]*ast.StructDecl{},
+		scope:   &scope{vars: map[string]Type{}, literals: map[string]*ast.FunctionLiteral{}},
+	}
+	for name, t := range builtins {
+		c.types[name] = t
+	}
+
+	declareTypes(program.Statements, c.types, c.impls, c.structs, func(enum *Basic, v *ast.EnumVariant) {
+		if len(v.Fields) == 0 {
+			c.bind(v.Name.Value, enum) // A bare variant is a value of the enum...
+			return
+		}
+		ctor := &Function{Return: enum} // ...and a variant with fields is a constructor for one
+		for range v.Fields {
+			ctor.Params = append(ctor.Params, Unknown)
+		}
+		c.bind(v.Name.Value, ctor)
+	})
+	for _, stmt := range program.Statements {
+		c.checkStatement(stmt)
+	}
+	return c.errors
+}
+
+// unknownFields returns the fields a struct literal sets that its struct does not declare, none if the struct is not declared in the program
+func unknownFields(structs map[string]*ast.StructDecl, lit *ast.StructLiteral) []*ast.Identifier {
+	decl, ok := structs[lit.Type.Value]
+	if !ok {
+		return nil
+	}
+	unknown := []*ast.Identifier{}
+	for _, f := range lit.Fields {
+		if !decl.HasField(f.Name.Value) {
+			unknown = append(unknown, f.Name)
+		}
+	}
+	return unknown
+}
+
+/*
+declareTypes records the structs, enums and traits a program declares, and which traits each
+type implements, before the program is checked, so annotations can name types declared later.
+
+@param statements []ast.Statement - The top-level statements of the program
+
+@param types map[string]Type - Filled with the declared types, by name
+
+@param impls map[string]map[string]bool - Filled with the traits each type implements
+
+@param structs map[string]*ast.StructDecl - Filled with the declared structs, by name
+
+@param variant func(*Basic, *ast.EnumVariant) - Called with the enum type for each enum variant
+*/
+func declareTypes(statements []ast.Statement, types map[string]Type, impls map[string]map[string]bool, structs map[string]*ast.StructDecl, variant func(*Basic, *ast.EnumVariant)) {
+	for _, stmt := range statements {
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = export.Declaration
+		}
+
+		switch decl := stmt.(type) {
+		case *ast.StructDecl:
+			types[decl.Name.Value] = &Basic{Name: decl.Name.Value}
+			structs[decl.Name.Value] = decl
+		case *ast.TraitDecl:
+			types[decl.Name.Value] = &Basic{Name: decl.Name.Value}
+		case *ast.EnumDecl:
+			enum := &Basic{Name: decl.Name.Value}
+			types[decl.Name.Value] = enum
+			for _, v := range decl.Variants {
+				variant(enum, v)
+			}
+		case *ast.ImplDecl:
+			if decl.Trait != nil {
+				if impls[decl.Type.Value] == nil {
+					impls[decl.Type.Value] = map[string]bool{}
+				}
+				impls[decl.Type.Value][decl.Trait.Value] = true
+			}
+		}
+	}
+}
+
+// checkStatement checks a statement and returns its type: the type of the expression of an expression statement, null otherwise
+func (c *checker) checkStatement(stmt ast.Statement) Type {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		c.checkLet(stmt)
+	case *ast.ReturnStatement:
+		t := Type(Null)
+		if stmt.ReturnValue != nil {
+			t = c.typeOf(stmt.ReturnValue)
+		}
+		if len(c.returns) > 0 && !c.assignable(t, c.returns[len(c.returns)-1]) {
+			c.errorf(stmt.Token, "cannot return %s from a function returning %s", t, c.returns[len(c.returns)-1])
+		}
+		if len(c.returned) > 0 {
+			c.returned[len(c.returned)-1] = true
+		}
+	case *ast.ExpressionStatement:
+		return c.typeOf(stmt.Expression)
+	case *ast.ThrowStatement:
+		c.typeOf(stmt.Value)
+	case *ast.ExportStatement:
+		c.checkStatement(stmt.Declaration)
+	case *ast.ImportStatement:
+		c.bind(stmt.Alias.Value, Unknown)
+	case *ast.TraitDecl:
+		for _, m := range stmt.Methods {
+			c.checkMethod(m, Unknown)
+		}
+	case *ast.ImplDecl:
+		self := c.types[stmt.Type.Value]
+		if self == nil {
+			self = Unknown
+		}
+		for _, m := range stmt.Methods {
+			c.checkMethod(m, self)
+		}
+	}
+	return Null
+}
+
+// checkLet checks the value of a let against its annotation and binds the names it declares
+func (c *checker) checkLet(stmt *ast.LetStatement) {
+	if stmt.Name == nil { // Destructured names could be anything
+		c.typeOf(stmt.Value)
+		for _, name := range ast.PatternNames(stmt.Pattern) {
+			c.bind(name, Unknown)
+		}
+		return
+	}
+
+	var declared Type
+	if stmt.Type != nil {
+		declared = c.resolve(stmt.Type)
+	}
+	lit := literalOf(stmt.Value, stmt.Name.Value)
+	if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok { // Bound before the body is checked, so recursive calls are checked too
+		if declared != nil {
+			c.bind(stmt.Name.Value, declared)
+		} else {
+			c.bind(stmt.Name.Value, c.signature(fn.Parameters, fn.ReturnType, fn.Generator))
+		}
+		c.scope.literals[stmt.Name.Value] = lit
+	}
+
+	t := c.typeOf(stmt.Value)
+	if declared == nil {
+		c.bind(stmt.Name.Value, t)
+	} else {
+		if !c.assignable(t, declared) {
+			c.errorf(start(stmt.Value), "cannot use %s as %s in let %s", t, declared, stmt.Name.Value)
+		}
+		c.bind(stmt.Name.Value, declared)
+	}
+	if lit != nil {
+		c.scope.literals[stmt.Name.Value] = lit
+	}
+}
+
+// literalOf returns the function literal exp is, with an arrow function turned into the equivalent fn, nil if exp is not a function literal
+func literalOf(exp ast.Expression, name string) *ast.FunctionLiteral {
+	switch exp := exp.(type) {
+	case *ast.FunctionLiteral:
+		return exp
+	case *ast.ArrowFunction:
+		return &ast.FunctionLiteral{Token: exp.Token, Name: name, Parameters: exp.Parameters}
+	}
+	return nil
+}
+
+// checkMethod checks a trait or impl method whose unannotated self parameter has the given type
+func (c *checker) checkMethod(m *ast.MethodDecl, self Type) {
+	if m.Body == nil { // A requirement only has a signature, whose annotations still have to name types
+		c.signature(m.Parameters, m.ReturnType, m.Generator)
+		return
+	}
+	c.checkFunction(m.Parameters, m.ReturnType, m.Body, m.Generator, self)
+}
+
+/*
+checkFunction checks the parameter defaults and the body of a function or method.
+
+@param params []*ast.Parameter - The parameters
+
+@param returnType ast.TypeAnnotation - The return annotation, nil if there is none
+
+@param body *ast.BlockStatement - The body
+
+@param generator bool - True if the body yields; the return type of a generator is not checked
+
+@param self Type - The type of an unannotated first parameter named self, nil for a function literal
+
+@return Type - The type of the function
+*/
+func (c *checker) checkFunction(params []*ast.Parameter, returnType ast.TypeAnnotation, body *ast.BlockStatement, generator bool, self Type) Type {
+	sig := c.signature(params, returnType, generator)
+
+	c.push()
+	defer c.pop()
+
+	for i, p := range params {
+		var t Type = Unknown
+		switch {
+		case p.Variadic:
+			t = Array
+		case i == 0 && self != nil && p.Name.Value == "self" && p.Type == nil:
+			t = self
+		default:
+			t = sig.Params[i]
+		}
+		if p.Default != nil {
+			if d := c.typeOf(p.Default); !c.assignable(d, t) {
+				c.errorf(start(p.Default), "cannot use %s as %s for parameter %s", d, t, p.Name.Value)
+			}
+		}
+		c.bind(p.Name.Value, t)
+	}
+
+	want := sig.Return
+	if generator {
+		want = Unknown
+	}
+	c.returns = append(c.returns, want)
+	c.returned = append(c.returned, false)
+	last, lastExp := c.checkBlock(body)
+	returned := c.returned[len(c.returned)-1]
+	c.returns = c.returns[:len(c.returns)-1]
+	c.returned = c.returned[:len(c.returned)-1]
+
+	switch {
+	case lastExp != nil: // The last expression is the implicit return value
+		if !c.assignable(last, want) {
+			c.errorf(start(lastExp), "cannot return %s from a function returning %s", last, want)
+		}
+	case !returned && !c.assignable(Null, want): // Falling off the end returns null
+		c.errorf(body.Token, "cannot return %s from a function returning %s", Null, want)
+	}
+	return sig
+}
+
+// signature returns the type of a function from its annotations; unannotated parts have type any
+func (c *checker) signature(params []*ast.Parameter, returnType ast.TypeAnnotation, generator bool) *Function {
+	sig := &Function{Params: []Type{}, Return: Unknown}
+	for _, p := range params {
+		if p.Variadic { // Extra arguments are not checked, they are collected into an array
+			sig.Variadic = true
+			break
+		}
+		if p.Type != nil {
+			sig.Params = append(sig.Params, c.resolve(p.Type))
+		} else {
+			sig.Params = append(sig.Params, Unknown)
+		}
+	}
+	if returnType != nil {
+		sig.Return = c.resolve(returnType)
+	}
+	if generator { // Calling a generator returns an iterator, not the values it yields
+		sig.Return = Unknown
+	}
+	return sig
+}
+
+/*
+checkBlock checks the statements of a block in a scope of their own.
+
+@return Type - The type of the last statement when it is an expression, null otherwise
+
+@return ast.Expression - The last expression, nil if the block does not end with one
+*/
+func (c *checker) checkBlock(block *ast.BlockStatement) (Type, ast.Expression) {
+	c.push()
+	defer c.pop()
+
+	var last Type = Null
+	var lastExp ast.Expression
+	for i, stmt := range block.Statements {
+		t := c.checkStatement(stmt)
+		if es, ok := stmt.(*ast.ExpressionStatement); ok && i == len(block.Statements)-1 {
+			last, lastExp = t, es.Expression
+		}
+	}
+	return last, lastExp
+}
+
+// typeOf checks an expression and returns its type
+func (c *checker) typeOf(exp ast.Expression) Type {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		return c.lookup(exp.Value)
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.StringLiteral:
+		return String
+	case *ast.InterpolatedString:
+		for _, e := range exp.Expressions {
+			c.typeOf(e)
+		}
+		return String
+	case *ast.Boolean:
+		return Bool
+	case *ast.NullLiteral:
+		return Null
+	case *ast.PrefixExpression:
+		right := c.typeOf(exp.Right)
+		if exp.Operator == "!" { // Negates the truthiness of any value
+			return Bool
+		}
+		if !c.assignable(right, Int) {
+			c.errorf(exp.Token, "operator %s cannot be applied to %s", exp.Operator, right)
+		}
+		return Int
+	case *ast.InfixExpression:
+		return c.infixType(exp)
+	case *ast.IndexExpression:
+		c.typeOf(exp.Left)
+		c.typeOf(exp.Index)
+	case *ast.SliceExpression:
+		return c.sliceType(exp)
+	case *ast.ArrayLiteral:
+		for _, el := range exp.Elements {
+			if spread, ok := el.(*ast.SpreadElement); ok {
+				c.checkSpread(spread, Array, "an array")
+			} else {
+				c.typeOf(el)
+			}
+		}
+		return Array
+	case *ast.HashLiteral:
+		for _, pair := range exp.Pairs {
+			if spread, ok := pair.Value.(*ast.SpreadElement); ok {
+				c.checkSpread(spread, Hash, "a hash")
+			} else {
+				c.typeOf(pair.Value)
+			}
+		}
+		return Hash
+	case *ast.RangeExpression:
+		for _, bound := range []ast.Expression{exp.Start, exp.End} {
+			if t := c.typeOf(bound); !c.assignable(t, Int) {
+				c.errorf(start(bound), "cannot use %s as int in a range", t)
+			}
+		}
+		return Range
+	case *ast.MemberExpression:
+		c.typeOf(exp.Object)
+	case *ast.FunctionLiteral:
+		return c.checkFunction(exp.Parameters, exp.ReturnType, exp.Body, exp.Generator, nil)
+	case *ast.ArrowFunction:
+		return c.checkFunction(exp.Parameters, nil, exp.Block(), false, nil)
+	case *ast.PipelineExpression:
+		return c.typeOf(exp.Desugar())
+	case *ast.CallExpression:
+		return c.callType(exp)
+	case *ast.NamedArgument:
+		return c.typeOf(exp.Value)
+	case *ast.YieldExpression:
+		c.typeOf(exp.Value)
+	case *ast.MatchExpression:
+		return c.matchType(exp)
+	case *ast.StructLiteral:
+		for _, f := range unknownFields(c.structs, exp) {
+			c.errorf(f.Token, "unknown field %s in %s", f.Value, exp.Type.Value)
+		}
+		for _, f := range exp.Fields {
+			c.typeOf(f.Value)
+		}
+		if t, ok := c.types[exp.Type.Value]; ok {
+			return t
+		}
+	case *ast.AssignExpression:
+		c.typeOf(exp.Target)
+		return c.typeOf(exp.Value)
+	case *ast.TryExpression:
+		c.checkBlock(exp.Block)
+		if exp.Catch != nil {
+			c.push()
+			if exp.CatchParam != nil {
+				c.bind(exp.CatchParam.Value, Unknown)
+			}
+			c.checkBlock(exp.Catch)
+			c.pop()
+		}
+		if exp.Finally != nil {
+			c.checkBlock(exp.Finally)
+		}
+	case *ast.SpawnExpression:
+		c.typeOf(exp.Call)
+	case *ast.SelectExpression:
+		for _, sc := range exp.Cases {
+			c.push()
+			if sc.Operation != nil {
+				c.typeOf(sc.Operation)
+			}
+			if sc.Binding != nil {
+				c.bind(sc.Binding.Value, Unknown)
+			}
+			c.typeOf(sc.Body)
+			c.pop()
+		}
+	}
+	return Unknown
+}
+
+// infixType checks the operands of a binary operator and returns the type of its result
+func (c *checker) infixType(exp *ast.InfixExpression) Type {
+	left, right := c.typeOf(exp.Left), c.typeOf(exp.Right)
+	mismatch := func() {
+		c.errorf(exp.Token, "operator %s cannot be applied to %s and %s", exp.Operator, left, right)
+	}
+
+	switch exp.Operator {
+	case "+", "<", ">", "<=", ">=": // Work on two ints or two strings
+		t := c.sameOf(left, right, Int, String)
+		if t == nil {
+			mismatch()
+			t = Unknown
+		}
+		if exp.Operator == "+" {
+			return t
+		}
+		return Bool
+	case "-", "*", "/", "%", "**", "&", "|", "^", "<<", ">>":
+		if !c.assignable(left, Int) || !c.assignable(right, Int) {
+			mismatch()
+		}
+		return Int
+	case "&&", "||": // Evaluate to whichever operand decides the result, so any value goes
+		if left == right {
+			return left
+		}
+	case "==", "!=":
+		return Bool
+	case "??":
+		if left == Null {
+			return right
+		}
+		if left == right {
+			return left
+		}
+	}
+	return Unknown
+}
+
+// sameOf returns which of the allowed types both operands have (any if one of them is any), nil if there is none
+func (c *checker) sameOf(left, right Type, allowed ...Type) Type {
+	for _, t := range allowed {
+		if c.assignable(left, t) && c.assignable(right, t) {
+			if left == Unknown && right == Unknown {
+				return Unknown
+			}
+			return t
+		}
+	}
+	return nil
+}
+
+// callType checks the arguments of a call against the parameters of the function and returns its return type
+func (c *checker) callType(call *ast.CallExpression) Type {
+	callee := c.typeOf(call.Function)
+	lit := c.calleeLiteral(call.Function)
+	if lit != nil {
+		if err := lit.CheckArguments(call.Arguments); err != nil {
+			c.errorf(start(call.Function), "%s", err)
+		}
+	}
+	args := make([]Type, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		if spread, ok := arg.(*ast.SpreadElement); ok {
+			c.checkSpread(spread, Array, "the arguments of "+call.Function.String())
+			args[i] = Unknown
+		} else {
+			args[i] = c.typeOf(arg)
+		}
+	}
+
+	switch fn := callee.(type) {
+	case *Function:
+		spread := false // The positional arguments after a spread land in unknown positions
+		for i, arg := range call.Arguments {
+			switch arg := arg.(type) {
+			case *ast.SpreadElement:
+				spread = true
+			case *ast.NamedArgument:
+				if p := parameterIndex(lit, arg.Name.Value); p >= 0 && p < len(fn.Params) && !c.assignable(args[i], fn.Params[p]) {
+					c.errorf(start(arg.Value), "cannot use %s as %s in argument %s of %s", args[i], fn.Params[p], arg.Name.Value, call.Function.String())
+				}
+			default:
+				if !spread && i < len(fn.Params) && !c.assignable(args[i], fn.Params[i]) {
+					c.errorf(start(arg), "cannot use %s as %s in argument %d of %s", args[i], fn.Params[i], i+1, call.Function.String())
+				}
+			}
+		}
+		return fn.Return
+	case *Basic:
+		c.errorf(start(call.Function), "cannot call %s of type %s", call.Function.String(), fn)
+	}
+	return Unknown
+}
+
+// matchType checks the arms of a match and returns the type they share, any if they differ
+func (c *checker) matchType(exp *ast.MatchExpression) Type {
+	c.typeOf(exp.Subject)
+
+	var result Type
+	for i, arm := range exp.Arms {
+		c.push()
+		for _, name := range ast.PatternNames(arm.Pattern) {
+			c.bind(name, Unknown)
+		}
+		if arm.Guard != nil {
+			if t := c.typeOf(arm.Guard); !c.assignable(t, Bool) {
+				c.errorf(start(arm.Guard), "match guard must be bool, got %s", t)
+			}
+		}
+		t := c.typeOf(arm.Body)
+		c.pop()
+
+		if i == 0 {
+			result = t
+		} else if result != t {
+			result = Unknown
+		}
+	}
+	if result == nil {
+		return Unknown
+	}
+	return result
+}
+
+// resolve turns an annotation into a type, reporting names that are not types
+func (c *checker) resolve(annotation ast.TypeAnnotation) Type {
+	switch a := annotation.(type) {
+	case *ast.NamedType:
+		if t, ok := c.types[a.Name]; ok {
+			return t
+		}
+		c.errorf(a.Token, "unknown type %s", a.Name)
+	case *ast.FunctionType:
+		fn := &Function{Return: c.resolve(a.Return)}
+		for _, p := range a.Parameters {
+			fn.Params = append(fn.Params, c.resolve(p))
+		}
+		return fn
+	}
+	return Unknown
+}
+
+/*
+assignable reports whether a value of type from can be used where a value of type to is
+expected: any goes with everything, a type implementing a trait can be used as the trait,
+and functions must agree on every parameter and on the return type.
+*/
+func (c *checker) assignable(from, to Type) bool {
+	if from == Unknown || to == Unknown {
+		return true
+	}
+
+	switch to := to.(type) {
+	case *Basic:
+		from, ok := from.(*Basic)
+		return ok && (from.Name == to.Name || c.impls[from.Name][to.Name])
+	case *Function:
+		from, ok := from.(*Function)
+		if !ok || len(from.Params) != len(to.Params) {
+			return false
+		}
+		for i := range to.Params {
+			if !c.assignable(to.Params[i], from.Params[i]) { // The function will be given what callers of to pass
+				return false
+			}
+		}
+		return c.assignable(from.Return, to.Return)
+	}
+	return false
+}
+
+// parameterIndex returns the position of the parameter called name, -1 if lit is nil or has no such parameter
+func parameterIndex(lit *ast.FunctionLiteral, name string) int {
+	if lit == nil {
+		return -1
+	}
+	for i, p := range lit.Parameters {
+		if p.Name.Value == name && !p.Variadic {
+			return i
+		}
+	}
+	return -1
+}
+
+// calleeLiteral returns the function literal a call calls, nil if it is not known (e.g. a parameter)
+func (c *checker) calleeLiteral(function ast.Expression) *ast.FunctionLiteral {
+	ident, ok := function.(*ast.Identifier)
+	if !ok {
+		return literalOf(function, "")
+	}
+	for s := c.scope; s != nil; s = s.outer {
+		if _, ok := s.vars[ident.Value]; ok {
+			return s.literals[ident.Value] // Nil if the innermost binding of the name is not a function literal
+		}
+	}
+	return nil
+}
+
+// push opens a new scope inside the current one
+func (c *checker) push() {
+	c.scope = &scope{vars: map[string]Type{}, literals: map[string]*ast.FunctionLiteral{}, outer: c.scope}
+}
+
+// pop closes the innermost scope
+func (c *checker) pop() { c.scope = c.scope.outer }
+
+// bind gives a name a type in the innermost scope, forgetting any function literal it was bound to
+func (c *checker) bind(name string, t Type) {
+	c.scope.vars[name] = t
+	delete(c.scope.literals, name)
+}
+
+// lookup returns the type of a name, any if it is not declared (e.g. a builtin)
+func (c *checker) lookup(name string) Type {
+	for s := c.scope; s != nil; s = s.outer {
+		if t, ok := s.vars[name]; ok {
+			return t
+		}
+	}
+	return Unknown
+}
+
+// checkSpread reports a spread whose value is not of the type it must have where it is spread (e.g. an array in a call)
+func (c *checker) checkSpread(spread *ast.SpreadElement, want Type, into string) {
+	if t := c.typeOf(spread.Value); !c.assignable(t, want) {
+		c.errorf(spread.Token, "cannot spread %s into %s; expected %s", t, into, want)
+	}
+}
+
+// sliceType checks the bounds of a slice and returns its type, which is the type of what is sliced
+func (c *checker) sliceType(exp *ast.SliceExpression) Type {
+	left := c.typeOf(exp.Left)
+	for _, bound := range []ast.Expression{exp.Start, exp.End, exp.Step} {
+		if bound == nil {
+			continue
+		}
+		if t := c.typeOf(bound); !c.assignable(t, Int) {
+			c.errorf(start(bound), "cannot use %s as int in a slice of %s", t, exp.Left.String())
+		}
+	}
+
+	switch left {
+	case String, Array, Unknown:
+		return left
+	}
+	c.errorf(start(exp.Left), "cannot slice %s of type %s", exp.Left.String(), left)
+	return Unknown
+}
+
+// errorf records an error at the position of tok
+func (c *checker) errorf(tok token.Token, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	c.errors = append(c.errors, fmt.Errorf("%s at %d:%d", msg, tok.Line, tok.Column))
+}
+
+// start returns the first token of an expression, where errors about it are reported
+func start(exp ast.Expression) token.Token {
+	switch exp := exp.(type) {
+	case *ast.InfixExpression:
+		return start(exp.Left)
+	case *ast.IndexExpression:
+		return start(exp.Left)
+	case *ast.SliceExpression:
+		return start(exp.Left)
+	case *ast.RangeExpression:
+		return start(exp.Start)
+	case *ast.PipelineExpression:
+		return start(exp.Left)
+	case *ast.ArrowFunction:
+		if len(exp.Parameters) > 0 && !exp.Parenthesized {
+			return exp.Parameters[0].Name.Token
+		}
+		return exp.Token
+	case *ast.MemberExpression:
+		return start(exp.Object)
+	case *ast.CallExpression:
+		return start(exp.Function)
+	case *ast.StructLiteral:
+		return exp.Type.Token
+	case *ast.AssignExpression:
+		return start(exp.Target)
+	case *ast.Identifier:
+		return exp.Token
+	case *ast.IntegerLiteral:
+		return exp.Token
+	case *ast.StringLiteral:
+		return exp.Token
+	case *ast.InterpolatedString:
+		return exp.Token
+	case *ast.Boolean:
+		return exp.Token
+	case *ast.NullLiteral:
+		return exp.Token
+	case *ast.PrefixExpression:
+		return exp.Token
+	case *ast.FunctionLiteral:
+		return exp.Token
+	case *ast.MacroLiteral:
+		return exp.Token
+	case *ast.NamedArgument:
+		return exp.Token
+	case *ast.YieldExpression:
+		return exp.Token
+	case *ast.MatchExpression:
+		return exp.Token
+	case *ast.TryExpression:
+		return exp.Token
+	case *ast.SpawnExpression:
+		return exp.Token
+	case *ast.SpreadElement:
+		return exp.Token
+	case *ast.ArrayLiteral:
+		return exp.Token
+	case *ast.HashLiteral:
+		return exp.Token
+	case *ast.SelectExpression:
+		return exp.Token
+	}
+	return token.Token{}
+}
@@ -0,0 +1,861 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Binding is a name declared at the top level of a program together with its inferred type
+type Binding struct {
+	Name  string      // The name, Type.method for a method of an impl
+	Token token.Token // Where the name is declared
+	Type  string      // The inferred type, with type variables named 'a, 'b, ... (e.g. fn('a) -> 'a)
+}
+
+// Inference is the result of Infer
+type Inference struct {
+	Bindings []Binding // The top-level lets and impl methods, in source order
+
+	uses []use // Every identifier in the program and its type, for TypeAt
+}
+
+// use is an identifier of the program, where a name is declared or used
+type use struct {
+	tok token.Token
+	t   Type
+}
+
+/*
+TypeAt answers hover-style queries: it returns the type of the name at a position.
+
+@param line int - The line of the position, starting at 1
+
+@param column int - The column of the position, starting at 1, anywhere within the name
+
+@return string - The name and its type (e.g. "id: fn(int) -> int")
+
+@return bool - False if there is no name at the position
+*/
+func (inf *Inference) TypeAt(line, column int) (string, bool) {
+	for _, u := range inf.uses {
+		length := utf8.RuneCountInString(u.tok.Literal)
+		if u.tok.Line == line && column >= u.tok.Column && column < u.tok.Column+length {
+			return u.tok.Literal + ": " + display(u.t)[0], true
+		}
+	}
+	return "", false
+}
+
+// scheme is a type whose variables stand for any type, which is what lets a polymorphic function be used at different types
+type scheme struct {
+	vars    []*Var // The quantified variables
+	t       Type
+	literal *ast.FunctionLiteral // The function literal the name is bound to, nil if it is not bound to one
+}
+
+// env maps the names visible in a block to their schemes
+type env struct {
+	vars  map[string]*scheme
+	outer *env
+}
+
+// frame is a function being inferred
+type frame struct {
+	ret      Type // The return type of the function
+	returned bool // True once a return statement has been seen
+}
+
+// inferer runs Hindley-Milner inference over a program
+type inferer struct {
+	errors   []error
+	types    map[string]Type            // The names usable in annotations
+	impls    map[string]map[string]bool // The traits each type implements
+	structs  map[string]*ast.StructDecl // The declared structs, by name
+	variants map[string]*Basic          // The enum each variant belongs to, by variant name
+	env      *env                       // The innermost scope
+	frames   []*frame                   // The functions being inferred, innermost last
+	methods  map[*ast.MethodDecl]Type   // The inferred types of impl methods
+	nextID   int                        // The ID of the next type variable
+	result   *Inference
+}
+
+/*
+Infer infers the types of a program with Hindley-Milner inference. Unlike Check, it needs
+no annotations: unannotated parameters get type variables which are worked out from how
+they are used, and lets are generalized, so fn(x) { x } has type fn('a) -> 'a and can be
+used at different types. Annotations are still honored, with any standing for a fresh variable.
+
+Inference is stricter than Check: every use of a value has to agree on its type, so it can
+reject programs that run fine.
+
+@param program *ast.Program - The parsed program
+
+@return *Inference - The inferred types of the top-level names, and of every name for TypeAt
+
+@return []error - The inference failures, each ending with the line:column it was found at
+*/
+func Infer(program *ast.Program) (*Inference, []error) {
+	in := &inferer{
+		errors:   []error{},
+		types:    map[string]Type{},
+		impls:    map[string]map[string]bool{},
+		structs:  map[string]*ast.StructDecl{},
+		variants: map[string]*Basic{},
+		methods:  map[*ast.MethodDecl]Type{},
+		env:      &env{vars: map[string]*scheme{}},
+		result:   &Inference{Bindings: []Binding{}},
+	}
+	for name, t := range builtins {
+		if name != "any" { // any means "a fresh variable" here, see resolve
+			in.types[name] = t
+		}
+	}
+
+	declareTypes(program.Statements, in.types, in.impls, in.structs, func(enum *Basic, v *ast.EnumVariant) {
+		in.variants[v.Name.Value] = enum
+		if len(v.Fields) == 0 {
+			in.env.vars[v.Name.Value] = &scheme{t: enum}
+			return
+		}
+		ctor := &Function{Return: enum} // Each field can hold any type
+		vars := []*Var{}
+		for range v.Fields {
+			field := in.fresh()
+			vars = append(vars, field)
+			ctor.Params = append(ctor.Params, field)
+		}
+		in.env.vars[v.Name.Value] = &scheme{vars: vars, t: ctor}
+	})
+
+	types := []Type{} // The type of each binding, formatted once every variable has been worked out
+	record := func(name string, tok token.Token, t Type) {
+		in.result.Bindings = append(in.result.Bindings, Binding{Name: name, Token: tok})
+		types = append(types, t)
+	}
+
+	for _, stmt := range program.Statements {
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = export.Declaration
+		}
+		in.inferStatement(stmt)
+
+		switch stmt := stmt.(type) {
+		case *ast.LetStatement:
+			if stmt.Name != nil {
+				record(stmt.Name.Value, stmt.Name.Token, in.lookup(stmt.Name.Value))
+			}
+		case *ast.ImplDecl:
+			for _, m := range stmt.Methods {
+				if t, ok := in.methods[m]; ok {
+					record(stmt.Type.Value+"."+m.Name.Value, m.Name.Token, t)
+				}
+			}
+		}
+	}
+
+	for i, t := range types {
+		in.result.Bindings[i].Type = display(t)[0]
+	}
+	return in.result, in.errors
+}
+
+// inferStatement infers a statement and returns its type: the type of the expression of an expression statement, null otherwise
+func (in *inferer) inferStatement(stmt ast.Statement) Type {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		in.inferLet(stmt)
+	case *ast.ReturnStatement:
+		t := Type(Null)
+		if stmt.ReturnValue != nil {
+			t = in.infer(stmt.ReturnValue)
+		}
+		if len(in.frames) > 0 {
+			f := in.frames[len(in.frames)-1]
+			f.returned = true
+			in.unify(f.ret, t, stmt.Token, "the return value")
+		}
+	case *ast.ExpressionStatement:
+		return in.infer(stmt.Expression)
+	case *ast.ThrowStatement:
+		in.infer(stmt.Value)
+	case *ast.ExportStatement:
+		in.inferStatement(stmt.Declaration)
+	case *ast.ImportStatement:
+		in.bindMono(stmt.Alias, in.fresh())
+	case *ast.TraitDecl:
+		for _, m := range stmt.Methods {
+			if m.Body != nil {
+				in.inferFunction(m.Parameters, m.ReturnType, m.Body, m.Generator, in.fresh())
+			}
+		}
+	case *ast.ImplDecl:
+		var self Type = in.fresh()
+		if t, ok := in.types[stmt.Type.Value]; ok {
+			self = t
+		}
+		for _, m := range stmt.Methods {
+			if m.Body != nil {
+				in.methods[m] = in.inferFunction(m.Parameters, m.ReturnType, m.Body, m.Generator, self)
+			}
+		}
+	}
+	return Null
+}
+
+// inferLet infers the value of a let and binds its names, generalizing the type of a plain let
+func (in *inferer) inferLet(stmt *ast.LetStatement) {
+	if stmt.Name == nil {
+		in.infer(stmt.Value)
+		for _, name := range ast.PatternNames(stmt.Pattern) {
+			in.env.vars[name] = &scheme{t: in.fresh()}
+		}
+		return
+	}
+
+	lit := literalOf(stmt.Value, stmt.Name.Value)
+	self := in.fresh() // The name is visible in its own value, monomorphically, so recursive functions can call themselves
+	in.push()
+	in.bindMono(stmt.Name, self)
+	in.env.vars[stmt.Name.Value].literal = lit
+	t := in.infer(stmt.Value)
+	in.pop()
+	in.unify(self, t, start(stmt.Value), "the recursive use of "+stmt.Name.Value)
+
+	if stmt.Type != nil {
+		in.unify(in.resolve(stmt.Type), t, start(stmt.Value), "let "+stmt.Name.Value)
+	}
+	s := in.generalize(t)
+	s.literal = lit
+	in.env.vars[stmt.Name.Value] = s
+}
+
+/*
+inferFunction infers the type of a function or method from its parameters, their defaults,
+its body and the return statements in it.
+
+@param self Type - The type of an unannotated first parameter named self, nil for a function literal
+
+@return *Function - The type of the function
+*/
+func (in *inferer) inferFunction(params []*ast.Parameter, returnType ast.TypeAnnotation, body *ast.BlockStatement, generator bool, self Type) *Function {
+	in.push()
+	defer in.pop()
+
+	fn := &Function{Params: []Type{}}
+	for i, p := range params {
+		var t Type
+		switch {
+		case p.Variadic: // Collects the extra arguments, whatever their annotation says about each of them
+			if p.Type != nil {
+				in.resolve(p.Type)
+			}
+			t = Array
+		case p.Type != nil:
+			t = in.resolve(p.Type)
+		case i == 0 && self != nil && p.Name.Value == "self":
+			t = self
+		default:
+			t = in.fresh()
+		}
+		if p.Default != nil {
+			in.unify(t, in.infer(p.Default), start(p.Default), "the default of parameter "+p.Name.Value)
+		}
+		in.bindMono(p.Name, t)
+		if p.Variadic {
+			fn.Variadic = true
+		} else {
+			fn.Params = append(fn.Params, t)
+		}
+	}
+
+	f := &frame{ret: in.fresh()}
+	if returnType != nil {
+		f.ret = in.resolve(returnType)
+	}
+	in.frames = append(in.frames, f)
+	last, lastExp := in.inferBlock(body)
+	in.frames = in.frames[:len(in.frames)-1]
+
+	switch {
+	case generator: // Calling a generator returns an iterator, whatever it yields
+		fn.Return = in.fresh()
+		return fn
+	case lastExp != nil:
+		in.unify(f.ret, last, start(lastExp), "the return value")
+	case !f.returned:
+		in.unify(f.ret, Null, body.Token, "the return value")
+	}
+	fn.Return = f.ret
+	return fn
+}
+
+// inferBlock infers the statements of a block in a scope of their own, returning the type and expression of its last statement like checkBlock
+func (in *inferer) inferBlock(block *ast.BlockStatement) (Type, ast.Expression) {
+	in.push()
+	defer in.pop()
+
+	var last Type = Null
+	var lastExp ast.Expression
+	for i, stmt := range block.Statements {
+		t := in.inferStatement(stmt)
+		if es, ok := stmt.(*ast.ExpressionStatement); ok && i == len(block.Statements)-1 {
+			last, lastExp = t, es.Expression
+		}
+	}
+	return last, lastExp
+}
+
+// infer infers the type of an expression
+func (in *inferer) infer(exp ast.Expression) Type {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		t := in.lookup(exp.Value)
+		in.result.uses = append(in.result.uses, use{exp.Token, t})
+		return t
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.StringLiteral:
+		return String
+	case *ast.InterpolatedString:
+		for _, e := range exp.Expressions {
+			in.infer(e)
+		}
+		return String
+	case *ast.Boolean:
+		return Bool
+	case *ast.NullLiteral:
+		return Null
+	case *ast.PrefixExpression:
+		right := in.infer(exp.Right)
+		if exp.Operator == "!" { // Negates the truthiness of any value
+			return Bool
+		}
+		in.unify(Int, right, exp.Token, "the operand of "+exp.Operator)
+		return Int
+	case *ast.InfixExpression:
+		return in.inferInfix(exp)
+	case *ast.IndexExpression:
+		in.infer(exp.Left)
+		in.infer(exp.Index)
+	case *ast.SliceExpression:
+		return in.inferSlice(exp)
+	case *ast.ArrayLiteral:
+		for _, el := range exp.Elements {
+			if spread, ok := el.(*ast.SpreadElement); ok {
+				in.inferSpread(spread, Array, "an array")
+			} else {
+				in.infer(el)
+			}
+		}
+		return Array
+	case *ast.HashLiteral:
+		for _, pair := range exp.Pairs {
+			if spread, ok := pair.Value.(*ast.SpreadElement); ok {
+				in.inferSpread(spread, Hash, "a hash")
+			} else {
+				in.infer(pair.Value)
+			}
+		}
+		return Hash
+	case *ast.RangeExpression:
+		in.unify(Int, in.infer(exp.Start), start(exp.Start), "a range")
+		in.unify(Int, in.infer(exp.End), start(exp.End), "a range")
+		return Range
+	case *ast.MemberExpression:
+		in.infer(exp.Object)
+	case *ast.FunctionLiteral:
+		return in.inferFunction(exp.Parameters, exp.ReturnType, exp.Body, exp.Generator, nil)
+	case *ast.ArrowFunction:
+		return in.inferFunction(exp.Parameters, nil, exp.Block(), false, nil)
+	case *ast.PipelineExpression:
+		return in.infer(exp.Desugar())
+	case *ast.CallExpression:
+		return in.inferCall(exp)
+	case *ast.NamedArgument:
+		return in.infer(exp.Value)
+	case *ast.YieldExpression:
+		if exp.Value != nil {
+			in.infer(exp.Value)
+		}
+	case *ast.MatchExpression:
+		return in.inferMatch(exp)
+	case *ast.StructLiteral:
+		for _, f := range unknownFields(in.structs, exp) {
+			in.errorf(f.Token, "unknown field %s in %s", f.Value, exp.Type.Value)
+		}
+		for _, f := range exp.Fields {
+			in.infer(f.Value)
+		}
+		if t, ok := in.types[exp.Type.Value]; ok {
+			return t
+		}
+	case *ast.AssignExpression:
+		in.infer(exp.Target)
+		return in.infer(exp.Value)
+	case *ast.TryExpression:
+		in.inferBlock(exp.Block)
+		if exp.Catch != nil {
+			in.push()
+			if exp.CatchParam != nil {
+				in.bindMono(exp.CatchParam, in.fresh())
+			}
+			in.inferBlock(exp.Catch)
+			in.pop()
+		}
+		if exp.Finally != nil {
+			in.inferBlock(exp.Finally)
+		}
+	case *ast.SpawnExpression:
+		in.infer(exp.Call)
+	case *ast.SelectExpression:
+		for _, sc := range exp.Cases {
+			in.push()
+			if sc.Operation != nil {
+				in.infer(sc.Operation)
+			}
+			if sc.Binding != nil {
+				in.bindMono(sc.Binding, in.fresh())
+			}
+			in.infer(sc.Body)
+			in.pop()
+		}
+	}
+	return in.fresh()
+}
+
+// inferInfix infers the operands of a binary operator and returns the type of its result
+func (in *inferer) inferInfix(exp *ast.InfixExpression) Type {
+	left, right := in.infer(exp.Left), in.infer(exp.Right)
+	mismatch := func() {
+		names := display(left, right)
+		in.errorf(exp.Token, "operator %s cannot be applied to %s and %s", exp.Operator, names[0], names[1])
+	}
+
+	switch exp.Operator {
+	case "+", "<", ">", "<=", ">=": // Two ints or two strings
+		if in.unifies(left, right) != nil {
+			mismatch()
+		} else if b, ok := prune(left).(*Basic); ok && b != Int && b != String {
+			mismatch()
+		}
+		if exp.Operator == "+" {
+			return left
+		}
+		return Bool
+	case "-", "*", "/", "%", "**", "&", "|", "^", "<<", ">>":
+		if in.unifies(Int, left) != nil || in.unifies(Int, right) != nil {
+			mismatch()
+		}
+		return Int
+	case "&&", "||": // Evaluate to whichever operand decides the result, so any value goes
+		if l, r := prune(left), prune(right); l == r {
+			return l
+		}
+	case "==", "!=":
+		return Bool
+	case "??":
+		if prune(left) == Null {
+			return right
+		}
+		if in.unifies(left, right) != nil {
+			mismatch()
+		}
+		return left
+	}
+	return in.fresh()
+}
+
+// inferSpread unifies the value of a spread with the type it must have where it is spread (e.g. an array in a call)
+func (in *inferer) inferSpread(spread *ast.SpreadElement, want Type, into string) {
+	in.unify(want, in.infer(spread.Value), spread.Token, "the spread into "+into)
+}
+
+// inferSlice unifies the bounds of a slice with int and returns its type, which is the type of what is sliced
+func (in *inferer) inferSlice(exp *ast.SliceExpression) Type {
+	left := in.infer(exp.Left)
+	for _, bound := range []ast.Expression{exp.Start, exp.End, exp.Step} {
+		if bound != nil {
+			in.unify(Int, in.infer(bound), start(bound), "a slice of "+exp.Left.String())
+		}
+	}
+
+	if b, ok := prune(left).(*Basic); ok && b != String && b != Array {
+		in.errorf(start(exp.Left), "cannot slice %s of type %s", exp.Left.String(), b)
+		return in.fresh()
+	}
+	return left
+}
+
+/*
+inferCall unifies the arguments of a call with the parameters of the function and returns its
+return type. The number of arguments and their names are checked against the function literal
+when the callee is a known one; otherwise only passing more arguments than the type has
+parameters is reported.
+*/
+func (in *inferer) inferCall(call *ast.CallExpression) Type {
+	callee := prune(in.infer(call.Function))
+	lit := in.calleeLiteral(call.Function)
+	if lit != nil {
+		if err := lit.CheckArguments(call.Arguments); err != nil {
+			in.errorf(start(call.Function), "%s", err)
+		}
+	}
+
+	positional := []ast.Expression{}
+	args := []Type{}
+	named := []*ast.NamedArgument{}
+	namedTypes := []Type{}
+	spread := false // Whether a spread hides how many positional arguments there are
+	for _, arg := range call.Arguments {
+		switch arg := arg.(type) {
+		case *ast.NamedArgument:
+			named = append(named, arg)
+			namedTypes = append(namedTypes, in.infer(arg))
+		case *ast.SpreadElement:
+			in.inferSpread(arg, Array, "the arguments of "+call.Function.String())
+			spread = true
+		default:
+			t := in.infer(arg)
+			if !spread { // The arguments after a spread land in unknown positions
+				positional = append(positional, arg)
+				args = append(args, t)
+			}
+		}
+	}
+
+	switch fn := callee.(type) {
+	case *Function:
+		if given := len(positional) + len(named); lit == nil && !spread && !fn.Variadic && given > len(fn.Params) {
+			in.errorf(start(call.Function), "too many arguments to %s: expected at most %d, got %d", call.Function.String(), len(fn.Params), given)
+		}
+		for i, arg := range positional {
+			if i < len(fn.Params) {
+				in.unify(fn.Params[i], args[i], start(arg), fmt.Sprintf("argument %d of %s", i+1, call.Function.String()))
+			}
+		}
+		for i, arg := range named {
+			if p := parameterIndex(lit, arg.Name.Value); p >= 0 && p < len(fn.Params) {
+				in.unify(fn.Params[p], namedTypes[i], start(arg.Value), fmt.Sprintf("argument %s of %s", arg.Name.Value, call.Function.String()))
+			}
+		}
+		return fn.Return
+	case *Var: // Calling an unknown value makes it a function
+		if spread { // ...of a number of parameters that is not known
+			return in.fresh()
+		}
+		ret := in.fresh()
+		in.unify(fn, &Function{Params: args, Return: ret}, start(call.Function), "the call of "+call.Function.String())
+		return ret
+	default:
+		in.errorf(start(call.Function), "cannot call %s of type %s", call.Function.String(), display(callee)[0])
+		return in.fresh()
+	}
+}
+
+// inferMatch infers the arms of a match; the patterns must fit the subject and the bodies must agree
+func (in *inferer) inferMatch(exp *ast.MatchExpression) Type {
+	subject := in.infer(exp.Subject)
+	result := in.fresh()
+
+	for _, arm := range exp.Arms {
+		in.push()
+		in.bindPattern(arm.Pattern, subject)
+		if arm.Guard != nil {
+			in.unify(Bool, in.infer(arm.Guard), start(arm.Guard), "the match guard")
+		}
+		in.unify(result, in.infer(arm.Body), start(arm.Body), "the match arm")
+		in.pop()
+	}
+	return result
+}
+
+// bindPattern unifies a pattern with the type of the value it matches and binds the names it introduces
+func (in *inferer) bindPattern(pattern ast.Pattern, subject Type) {
+	switch p := pattern.(type) {
+	case *ast.LiteralPattern:
+		in.unify(subject, in.infer(p.Value), p.Token, "the pattern")
+	case *ast.BindingPattern:
+		in.bindMono(p.Name, subject)
+	case *ast.VariantPattern:
+		if enum, ok := in.variants[p.Name.Value]; ok {
+			in.unify(subject, enum, p.Token, "the pattern")
+		}
+		for _, arg := range p.Arguments {
+			in.bindPattern(arg, in.fresh())
+		}
+	case *ast.WildcardPattern:
+	default: // Arrays and hashes have no types of their own yet
+		for _, name := range ast.PatternNames(pattern) {
+			in.env.vars[name] = &scheme{t: in.fresh()}
+		}
+	}
+}
+
+// resolve turns an annotation into a type; any stands for a fresh variable
+func (in *inferer) resolve(annotation ast.TypeAnnotation) Type {
+	switch a := annotation.(type) {
+	case *ast.NamedType:
+		if t, ok := in.types[a.Name]; ok {
+			return t
+		}
+		if a.Name != "any" {
+			in.errorf(a.Token, "unknown type %s", a.Name)
+		}
+	case *ast.FunctionType:
+		fn := &Function{Return: in.resolve(a.Return)}
+		for _, p := range a.Parameters {
+			fn.Params = append(fn.Params, in.resolve(p))
+		}
+		return fn
+	}
+	return in.fresh()
+}
+
+// errInfinite is returned by unifies when a variable would have to contain itself (e.g. fn(x) { x(x) })
+var errInfinite = errors.New("infinite type")
+
+// errMismatch is returned by unifies when two types cannot be made equal
+var errMismatch = errors.New("type mismatch")
+
+// unify makes want and got the same type, recording an error about what (e.g. "let x") at tok if they cannot be
+func (in *inferer) unify(want, got Type, tok token.Token, what string) {
+	names := display(want, got) // Before unifying, which changes the variables
+	switch in.unifies(want, got) {
+	case errInfinite:
+		in.errorf(tok, "infinite type: %s = %s in %s", names[0], names[1], what)
+	case errMismatch:
+		in.errorf(tok, "expected %s, got %s in %s", names[0], names[1], what)
+	}
+}
+
+// unifies makes two types the same by instantiating their variables
+func (in *inferer) unifies(a, b Type) error {
+	a, b = prune(a), prune(b)
+
+	if v, ok := a.(*Var); ok {
+		if v == b {
+			return nil
+		}
+		if occurs(v, b) {
+			return errInfinite
+		}
+		v.Instance = b
+		return nil
+	}
+	if _, ok := b.(*Var); ok {
+		return in.unifies(b, a)
+	}
+
+	switch a := a.(type) {
+	case *Basic:
+		if b, ok := b.(*Basic); ok && (a == b || in.impls[a.Name][b.Name] || in.impls[b.Name][a.Name]) {
+			return nil
+		}
+	case *Function:
+		b, ok := b.(*Function)
+		if !ok || len(a.Params) != len(b.Params) {
+			return errMismatch
+		}
+		for i := range a.Params {
+			if err := in.unifies(a.Params[i], b.Params[i]); err != nil {
+				return err
+			}
+		}
+		return in.unifies(a.Return, b.Return)
+	}
+	return errMismatch
+}
+
+// generalize quantifies the variables of t that no enclosing scope refers to
+func (in *inferer) generalize(t Type) *scheme {
+	bound := map[*Var]bool{}
+	for e := in.env; e != nil; e = e.outer {
+		for _, s := range e.vars {
+			quantified := map[*Var]bool{}
+			for _, v := range s.vars {
+				quantified[v] = true
+			}
+			for _, v := range freeVars(s.t) {
+				if !quantified[v] {
+					bound[v] = true
+				}
+			}
+		}
+	}
+
+	s := &scheme{t: t}
+	for _, v := range freeVars(t) {
+		if !bound[v] {
+			s.vars = append(s.vars, v)
+		}
+	}
+	return s
+}
+
+// instantiate returns the type of a scheme with fresh variables for its quantified ones
+func (in *inferer) instantiate(s *scheme) Type {
+	if len(s.vars) == 0 {
+		return s.t
+	}
+	fresh := map[*Var]Type{}
+	for _, v := range s.vars {
+		fresh[v] = in.fresh()
+	}
+
+	var copyType func(Type) Type
+	copyType = func(t Type) Type {
+		switch t := prune(t).(type) {
+		case *Var:
+			if f, ok := fresh[t]; ok {
+				return f
+			}
+			return t
+		case *Function:
+			fn := &Function{Return: copyType(t.Return), Variadic: t.Variadic}
+			for _, p := range t.Params {
+				fn.Params = append(fn.Params, copyType(p))
+			}
+			return fn
+		default:
+			return t
+		}
+	}
+	return copyType(s.t)
+}
+
+// fresh returns a new type variable
+func (in *inferer) fresh() *Var {
+	in.nextID++
+	return &Var{ID: in.nextID}
+}
+
+// push opens a new scope inside the current one
+func (in *inferer) push() { in.env = &env{vars: map[string]*scheme{}, outer: in.env} }
+
+// pop closes the innermost scope
+func (in *inferer) pop() { in.env = in.env.outer }
+
+// bindMono binds a name to a type that is not generalized (e.g. a parameter, whose type is the same everywhere in the body)
+func (in *inferer) bindMono(name *ast.Identifier, t Type) {
+	in.env.vars[name.Value] = &scheme{t: t}
+	in.result.uses = append(in.result.uses, use{name.Token, t})
+}
+
+// calleeLiteral returns the function literal a call calls, nil if it is not known (e.g. a parameter)
+func (in *inferer) calleeLiteral(function ast.Expression) *ast.FunctionLiteral {
+	ident, ok := function.(*ast.Identifier)
+	if !ok {
+		return literalOf(function, "")
+	}
+	for e := in.env; e != nil; e = e.outer {
+		if s, ok := e.vars[ident.Value]; ok {
+			return s.literal
+		}
+	}
+	return nil
+}
+
+// lookup returns a fresh instance of the type of a name; a name that is not declared (e.g. a builtin) gets a fresh variable
+func (in *inferer) lookup(name string) Type {
+	for e := in.env; e != nil; e = e.outer {
+		if s, ok := e.vars[name]; ok {
+			return in.instantiate(s)
+		}
+	}
+	return in.fresh()
+}
+
+// errorf records an error at the position of tok
+func (in *inferer) errorf(tok token.Token, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	in.errors = append(in.errors, fmt.Errorf("%s at %d:%d", msg, tok.Line, tok.Column))
+}
+
+// prune follows instantiated variables to the type they stand for
+func prune(t Type) Type {
+	for {
+		v, ok := t.(*Var)
+		if !ok || v.Instance == nil {
+			return t
+		}
+		t = v.Instance
+	}
+}
+
+// occurs reports whether v appears in t
+func occurs(v *Var, t Type) bool {
+	for _, free := range freeVars(t) {
+		if free == v {
+			return true
+		}
+	}
+	return false
+}
+
+// freeVars returns the variables of t that are still unknown, in order of appearance
+func freeVars(t Type) []*Var {
+	switch t := prune(t).(type) {
+	case *Var:
+		return []*Var{t}
+	case *Function:
+		vars := []*Var{}
+		seen := map[*Var]bool{}
+		for _, part := range append(append([]Type{}, t.Params...), t.Return) {
+			for _, v := range freeVars(part) {
+				if !seen[v] {
+					seen[v] = true
+					vars = append(vars, v)
+				}
+			}
+		}
+		return vars
+	}
+	return nil
+}
+
+// display formats types for messages, naming their unknown variables 'a, 'b, ... in order of appearance across all of them
+func display(types ...Type) []string {
+	names := map[*Var]string{}
+
+	var format func(Type) string
+	format = func(t Type) string {
+		switch t := prune(t).(type) {
+		case *Var:
+			if _, ok := names[t]; !ok {
+				names[t] = varName(len(names))
+			}
+			return names[t]
+		case *Function:
+			params := []string{}
+			for _, p := range t.Params {
+				params = append(params, format(p))
+			}
+			if t.Variadic {
+				params = append(params, "..."+Array.String())
+			}
+			return "fn(" + strings.Join(params, ", ") + ") -> " + format(t.Return)
+		default:
+			return t.String()
+		}
+	}
+
+	out := []string{}
+	for _, t := range types {
+		out = append(out, format(t))
+	}
+	return out
+}
+
+// varName returns the name of the n-th type variable: 'a to 'z, then 'a1, 'b1, ...
+func varName(n int) string {
+	name := "'" + string(rune('a'+n%26))
+	if n >= 26 {
+		name += fmt.Sprint(n / 26)
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is a type as seen by the checker
+type Type interface {
+	String() string
+}
+
+// Basic is a type known by name: int, string, bool, null, or a declared struct, enum or trait
+type Basic struct {
+	Name string // The name of the type
+}
+
+func (b *Basic) String() string { return b.Name }
+
+// Function is the type of a function (e.g. fn(int, string) -> bool)
+type Function struct {
+	Params   []Type // The parameter types, in order, without a rest parameter
+	Return   Type   // The type of the value the function returns
+	Variadic bool   // True if a rest parameter collects any extra arguments into an array
+}
+
+// Returns the type as it is written in annotations, with a rest parameter shown as ...array
+func (f *Function) String() string {
+	params := []string{}
+	for _, p := range f.Params {
+		params = append(params, p.String())
+	}
+	if f.Variadic {
+		params = append(params, "..."+Array.String())
+	}
+	return "fn(" + strings.Join(params, ", ") + ") -> " + f.Return.String()
+}
+
+// Any is the type of values the checker knows nothing about, such as unannotated parameters.
+// It is compatible with every type, which lets annotated and unannotated code mix.
+type Any struct{}
+
+func (a *Any) String() string { return "any" }
+
+// Var is a type variable: a type that inference has not worked out yet
+type Var struct {
+	ID       int  // Tells variables apart
+	Instance Type // The type the variable was unified with, nil while it is still unknown
+}
+
+// Returns the type the variable stands for, or its placeholder name (e.g. t3) while it is unknown
+func (v *Var) String() string {
+	if v.Instance != nil {
+		return v.Instance.String()
+	}
+	return fmt.Sprintf("t%d", v.ID)
+}
+
+// The built-in types
+var (
+	Int          = &Basic{Name: "int"}
+	String       = &Basic{Name: "string"}
+	Bool         = &Basic{Name: "bool"}
+	Null         = &Basic{Name: "null"}
+	Range        = &Basic{Name: "range"}
+	Array        = &Basic{Name: "array"}
+	Hash         = &Basic{Name: "hash"}
+	Unknown Type = &Any{}
+)
+
+// builtins are the type names every program can use in annotations
+var builtins = map[string]Type{
+	"int":    Int,
+	"string": String,
+	"bool":   Bool,
+	"null":   Null,
+	"range":  Range,
+	"array":  Array,
+	"hash":   Hash,
+	"any":    Unknown,
+}
@@ -0,0 +1,255 @@
+package integer
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/big"
+)
+
+/*
+Int is a BroLang integer. It holds an int64 while the value fits in one and switches to a
+math/big integer when it does not, so arithmetic never overflows. Every result is put back
+into the small form when it fits again, which means a value has exactly one representation:
+two Ints are equal exactly when their small or big parts are, and print and hash the same way.
+
+The zero Int is 0.
+*/
+type Int struct {
+	small int64    // The value, while big is nil
+	big   *big.Int // The value when it does not fit in an int64, nil otherwise. Never modified once set.
+}
+
+// The errors arithmetic can fail with
+var (
+	ErrDivisionByZero   = errors.New("division by zero")
+	ErrNegativeExponent = errors.New("negative exponent")
+	ErrTooLarge         = errors.New("result too large")
+)
+
+// MaxPowBits is the most bits a result of Pow may need; larger powers fail instead of using up all memory
+const MaxPowBits = 1 << 20
+
+// New returns the Int for an int64
+func New(n int64) Int {
+	return Int{small: n}
+}
+
+/*
+FromBig returns the Int for a big integer, in the small form if it fits in an int64.
+
+@param n *big.Int - The value, which is copied
+
+@return Int - The Int with the same value
+*/
+func FromBig(n *big.Int) Int {
+	if n.IsInt64() {
+		return Int{small: n.Int64()}
+	}
+	return Int{big: new(big.Int).Set(n)}
+}
+
+/*
+Parse reads a decimal integer literal of any length. Leading zeros do not change the base,
+so 010 is 10, and base prefixes such as 0x are not accepted.
+
+@param literal string - The literal, without a sign
+
+@return Int - The value of the literal
+
+@return bool - False if the literal is not a decimal integer
+*/
+func Parse(literal string) (Int, bool) {
+	n, ok := new(big.Int).SetString(literal, 10)
+	if !ok {
+		return Int{}, false
+	}
+	return FromBig(n), true
+}
+
+// Int64 returns the value as an int64, and false if it does not fit in one
+func (x Int) Int64() (int64, bool) {
+	return x.small, x.big == nil
+}
+
+// Big returns the value as a new big integer, whichever form it is in
+func (x Int) Big() *big.Int {
+	if x.big == nil {
+		return big.NewInt(x.small)
+	}
+	return new(big.Int).Set(x.big)
+}
+
+// IsBig reports whether the value needs the big form
+func (x Int) IsBig() bool {
+	return x.big != nil
+}
+
+// String returns the value in decimal (e.g. -12 or 18446744073709551616)
+func (x Int) String() string {
+	if x.big == nil {
+		return big.NewInt(x.small).String()
+	}
+	return x.big.String()
+}
+
+// Sign returns -1, 0 or +1 as the value is negative, zero or positive
+func (x Int) Sign() int {
+	if x.big != nil {
+		return x.big.Sign()
+	}
+	switch {
+	case x.small < 0:
+		return -1
+	case x.small > 0:
+		return 1
+	}
+	return 0
+}
+
+// Cmp returns -1, 0 or +1 as x is less than, equal to or greater than y
+func (x Int) Cmp(y Int) int {
+	if x.big == nil && y.big == nil {
+		switch {
+		case x.small < y.small:
+			return -1
+		case x.small > y.small:
+			return 1
+		}
+		return 0
+	}
+	return x.Big().Cmp(y.Big())
+}
+
+/*
+HashKey returns a hash of the value for use as a hash map key. Since every value has one
+form, equal Ints always have equal keys.
+
+@return uint64 - The key: the value itself in the small form, a hash of its digits in the big form
+*/
+func (x Int) HashKey() uint64 {
+	if x.big == nil {
+		return uint64(x.small)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(x.big.String()))
+	return h.Sum64()
+}
+
+// Neg returns -x
+func (x Int) Neg() Int {
+	if x.big == nil && x.small != math.MinInt64 {
+		return Int{small: -x.small}
+	}
+	return FromBig(x.Big().Neg(x.Big()))
+}
+
+// Add returns x + y
+func (x Int) Add(y Int) Int {
+	if x.big == nil && y.big == nil {
+		sum := x.small + y.small
+		if (sum > x.small) == (y.small > 0) { // The sum moved the way y points, so it did not wrap around
+			return Int{small: sum}
+		}
+	}
+	return FromBig(new(big.Int).Add(x.Big(), y.Big()))
+}
+
+// Sub returns x - y
+func (x Int) Sub(y Int) Int {
+	if x.big == nil && y.big == nil {
+		diff := x.small - y.small
+		if (diff < x.small) == (y.small > 0) { // The difference moved against y, so it did not wrap around
+			return Int{small: diff}
+		}
+	}
+	return FromBig(new(big.Int).Sub(x.Big(), y.Big()))
+}
+
+// Mul returns x * y
+func (x Int) Mul(y Int) Int {
+	if x.big == nil && y.big == nil {
+		if x.small == 0 || y.small == 0 {
+			return Int{}
+		}
+		product := x.small * y.small
+		if product/y.small == x.small && !(x.small == -1 && y.small == math.MinInt64) && !(y.small == -1 && x.small == math.MinInt64) {
+			return Int{small: product}
+		}
+	}
+	return FromBig(new(big.Int).Mul(x.Big(), y.Big()))
+}
+
+/*
+Quo returns x / y rounded towards zero, like Go's / on ints.
+
+@param y Int - The divisor
+
+@return Int - The quotient
+
+@return error - ErrDivisionByZero if y is 0
+*/
+func (x Int) Quo(y Int) (Int, error) {
+	if y.Sign() == 0 {
+		return Int{}, ErrDivisionByZero
+	}
+	if x.big == nil && y.big == nil && !(x.small == math.MinInt64 && y.small == -1) {
+		return Int{small: x.small / y.small}, nil
+	}
+	return FromBig(new(big.Int).Quo(x.Big(), y.Big())), nil
+}
+
+/*
+Rem returns the remainder of x / y, which has the sign of x like Go's % on ints.
+
+@param y Int - The divisor
+
+@return Int - The remainder
+
+@return error - ErrDivisionByZero if y is 0
+*/
+func (x Int) Rem(y Int) (Int, error) {
+	if y.Sign() == 0 {
+		return Int{}, ErrDivisionByZero
+	}
+	if x.big == nil && y.big == nil {
+		if y.small == -1 { // MinInt64 % -1 would overflow in Go, but the remainder is 0 anyway
+			return Int{}, nil
+		}
+		return Int{small: x.small % y.small}, nil
+	}
+	return FromBig(new(big.Int).Rem(x.Big(), y.Big())), nil
+}
+
+/*
+Pow returns x ** y. Powers of 0, 1 and -1 are worked out for any exponent; other powers
+fail when the result could need more than MaxPowBits bits.
+
+@param y Int - The exponent
+
+@return Int - The power
+
+@return error - ErrNegativeExponent if y is negative, as the result would not be an integer, or ErrTooLarge
+*/
+func (x Int) Pow(y Int) (Int, error) {
+	switch {
+	case y.Sign() < 0:
+		return Int{}, ErrNegativeExponent
+	case y.Sign() == 0:
+		return New(1), nil
+	case x.Sign() == 0 || x.Cmp(New(1)) == 0:
+		return x, nil
+	case x.Cmp(New(-1)) == 0:
+		if y.Big().Bit(0) == 0 {
+			return New(1), nil
+		}
+		return x, nil
+	}
+
+	// The result needs at most (bits of |x|) * y bits
+	n, ok := y.Int64()
+	if !ok || n > MaxPowBits/int64(x.Big().BitLen()) {
+		return Int{}, ErrTooLarge
+	}
+	return FromBig(new(big.Int).Exp(x.Big(), y.Big(), nil)), nil
+}
@@ -1,56 +1,184 @@
 package lexer
 
-import "github.com/kriptonian1/BroLang/src/token"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kriptonian1/BroLang/src/token"
+)
 
 type Lexer struct {
 	input        string // Input to be tokenized
 	position     int    // Current position in input (points to current char)
 	readPosition int    // Current reading position in input (after current char)
-	ch           byte   // Current char under examination
+	ch           rune   // Current char under examination
+	line         int    // Line of the current char (starting at 1)
+	column       int    // Column of the current char in characters, not bytes (starting at 1)
+
+	// One entry per ${ we are inside of, counting the { opened since then.
+	// A } seen while the top entry is 0 closes the interpolation and resumes the string.
+	interpolations []int
+
+	dialect *token.Dialect // The keyword table identifiers are looked up in
+	err     error          // Set when the dialect pragma names a dialect that does not exist
+}
+
+// Option configures a lexer created with New
+type Option func(*Lexer)
+
+// WithDialect makes the lexer use the keywords of a dialect, unless the input selects one with a pragma
+func WithDialect(d *token.Dialect) Option {
+	return func(l *Lexer) {
+		if d != nil {
+			l.dialect = d
+		}
+	}
 }
 
 /*
-New creates a new lexer instance
+New creates a new lexer instance. The keywords are the classic ones unless an option
+or a dialect pragma on the first line of the input (e.g. // dialect: bro) says otherwise;
+the pragma wins, so every file can choose its own dialect.
 
 @param input string - Input to be tokenized
 
+@param opts ...Option - Options such as WithDialect
+
 @return *Lexer - A new lexer instance
 */
-func New(input string) *Lexer {
-	l := &Lexer{input: input} // Create a new lexer instance with the input
-	l.readChar()              // Read the first character in the input
+func New(input string, opts ...Option) *Lexer {
+	l := &Lexer{input: input, line: 1, dialect: token.Classic} // Create a new lexer instance with the input
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if name, ok := Pragma(input); ok {
+		if d, ok := token.LookupDialect(name); ok {
+			l.dialect = d
+		} else {
+			l.err = fmt.Errorf("unknown dialect %q at 1:1 (known dialects: %s)", name, strings.Join(token.DialectNames(), ", "))
+		}
+	}
+
+	l.readChar() // Read the first character in the input
 	return l
 }
 
+// pragmaPrefix starts the comment that selects the dialect of a file
+const pragmaPrefix = "// dialect:"
+
+/*
+Pragma returns the dialect named by a pragma comment on the first line of the input
+(e.g. // dialect: bro).
+
+@param input string - The source
+
+@return string - The name of the dialect
+
+@return bool - False if the first line is not a pragma
+*/
+func Pragma(input string) (string, bool) {
+	first := input
+	if i := strings.IndexByte(input, '\n'); i >= 0 {
+		first = input[:i]
+	}
+	first = strings.TrimSpace(first)
+	if !strings.HasPrefix(first, pragmaPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(first, pragmaPrefix)), true
+}
+
+// Dialect returns the dialect the lexer reads keywords in
+func (l *Lexer) Dialect() *token.Dialect {
+	return l.dialect
+}
+
+// Err returns the problem with the dialect pragma of the input, nil if there is none
+func (l *Lexer) Err() error {
+	return l.err
+}
+
 /*
-readChar reads the next character in the input and advances the position in the input string
+readChar decodes the next UTF-8 character in the input and advances the position in the input string.
+Invalid UTF-8 is read one byte at a time as utf8.RuneError.
 */
 func (l *Lexer) readChar() {
+	if l.ch == '\n' { // The char we are leaving ends a line
+		l.line++
+		l.column = 0
+	}
+
+	width := 1
 	if l.readPosition >= len(l.input) {
 		l.ch = 0 // ASCII code for "NUL"
 	} else {
-		l.ch = l.input[l.readPosition] // Get the current character
+		l.ch, width = utf8.DecodeRuneInString(l.input[l.readPosition:]) // Get the current character
 	}
 	l.position = l.readPosition // Update the position to the read position (current position)
-	l.readPosition += 1         // Increment the read position
+	l.readPosition += width     // Advance past every byte of the character
+	l.column++
 }
 
+/*
+NextToken returns the next token in the input, stamped with the line and column it starts at
+
+@return token.Token - The next token (token.EOF once the input is exhausted)
+*/
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
-	l.skipWhitespace() // Skip the whitespace
+	for { // Skip whitespace and comments until we reach something that can start a token
+		l.skipWhitespace()
+		if l.ch == '/' && l.peakChar() == '/' && !l.atDocComment() {
+			l.skipLineComment()
+			continue
+		}
+		if l.ch == '/' && l.peakChar() == '*' {
+			line, column, offset := l.line, l.column, l.position
+			if !l.skipBlockComment() {
+				return token.Token{Type: token.ILLEGAL, Literal: "/*", Line: line, Column: column, Offset: offset} // Unterminated block comment
+			}
+			continue
+		}
+		break
+	}
+
+	line, column, offset := l.line, l.column, l.position
+	tok = l.readToken()
+	tok.Line, tok.Column, tok.Offset = line, column, offset
+	return tok
+}
+
+// readToken reads the token that starts at the current char
+func (l *Lexer) readToken() token.Token {
+	var tok token.Token
 
 	switch l.ch {
 	case '=':
 		if l.peakChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok = token.Token{Type: token.EQ, Literal: string(ch) + string(l.ch)} // ==
+			tok = l.newTwoCharToken(token.EQ) // ==
+		} else if l.peakChar() == '>' {
+			tok = l.newTwoCharToken(token.FAT_ARROW) // =>
 		} else {
 			tok = newToken(token.ASSIGN, l.ch) // =
 		}
 	case ';':
 		tok = newToken(token.SEMICOLON, l.ch)
+	case ':':
+		tok = newToken(token.COLON, l.ch)
+	case '.':
+		if strings.HasPrefix(l.input[l.position:], "...") {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else if l.peakChar() == '.' {
+			tok = l.newTwoCharToken(token.DOT_DOT) // ..
+		} else {
+			tok = newToken(token.DOT, l.ch)
+		}
 	case '(':
 		tok = newToken(token.LPAREN, l.ch)
 	case ')':
@@ -60,42 +188,110 @@ func (l *Lexer) NextToken() token.Token {
 	case '+':
 		tok = newToken(token.PLUS, l.ch)
 	case '-':
-		tok = newToken(token.MINUS, l.ch)
+		if l.peakChar() == '>' {
+			tok = l.newTwoCharToken(token.ARROW) // ->
+		} else {
+			tok = newToken(token.MINUS, l.ch)
+		}
 	case '!':
 		if l.peakChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok = token.Token{Type: token.NOT_EQ, Literal: string(ch) + string(l.ch)} // !=
+			tok = l.newTwoCharToken(token.NOT_EQ) // !=
 		} else {
 			tok = newToken(token.BANG, l.ch)
 		}
 	case '/':
+		if l.atDocComment() {
+			tok.Literal = l.readDocComment()
+			tok.Type = token.DOC_COMMENT
+			return tok
+		}
 		tok = newToken(token.SLASH, l.ch)
 	case '*':
-		tok = newToken(token.ASTERISK, l.ch)
+		if l.peakChar() == '*' {
+			tok = l.newTwoCharToken(token.POWER) // **
+		} else {
+			tok = newToken(token.ASTERISK, l.ch)
+		}
+	case '%':
+		tok = newToken(token.PERCENT, l.ch)
+	case '&':
+		if l.peakChar() == '&' {
+			tok = l.newTwoCharToken(token.AND) // &&
+		} else {
+			tok = newToken(token.BIT_AND, l.ch)
+		}
+	case '|':
+		if l.peakChar() == '|' {
+			tok = l.newTwoCharToken(token.OR) // ||
+		} else if l.peakChar() == '>' {
+			tok = l.newTwoCharToken(token.PIPE) // |>
+		} else {
+			tok = newToken(token.BIT_OR, l.ch)
+		}
+	case '?':
+		if l.peakChar() == '.' {
+			tok = l.newTwoCharToken(token.OPTIONAL_CHAIN) // ?.
+		} else if l.peakChar() == '?' {
+			tok = l.newTwoCharToken(token.NULLISH) // ??
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
+	case '^':
+		tok = newToken(token.BIT_XOR, l.ch)
+	case '~':
+		tok = newToken(token.BIT_NOT, l.ch)
 	case '{':
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1]++
+		}
 		tok = newToken(token.LBRACE, l.ch)
 	case '}':
+		if n := len(l.interpolations); n > 0 {
+			if l.interpolations[n-1] == 0 { // End of ${...}, back to string mode
+				l.interpolations = l.interpolations[:n-1]
+				return l.readString(token.INTERP_MIDDLE, token.INTERP_TAIL)
+			}
+			l.interpolations[n-1]--
+		}
 		tok = newToken(token.RBRACE, l.ch)
+	case '[':
+		tok = newToken(token.LBRACKET, l.ch)
+	case ']':
+		tok = newToken(token.RBRACKET, l.ch)
+	case '"':
+		return l.readString(token.INTERP_HEAD, token.STRING)
 	case '<':
-		tok = newToken(token.LT, l.ch)
+		if l.peakChar() == '=' {
+			tok = l.newTwoCharToken(token.LT_EQ) // <=
+		} else if l.peakChar() == '<' {
+			tok = l.newTwoCharToken(token.SHIFT_LEFT) // <<
+		} else {
+			tok = newToken(token.LT, l.ch)
+		}
 	case '>':
-		tok = newToken(token.GT, l.ch)
+		if l.peakChar() == '=' {
+			tok = l.newTwoCharToken(token.GT_EQ) // >=
+		} else if l.peakChar() == '>' {
+			tok = l.newTwoCharToken(token.SHIFT_RIGHT) // >>
+		} else {
+			tok = newToken(token.GT, l.ch)
+		}
 	case 0:
 		tok.Literal = "" // End of file
 		tok.Type = token.EOF
 
 	default:
 		if isLetter(l.ch) {
-			tok.Literal = l.readIdentifier()          // Read the identifier
-			tok.Type = token.LookupIdent(tok.Literal) // Lookup the identifier in the keywords table
+			tok.Literal = l.readIdentifier()              // Read the identifier
+			tok.Type = l.dialect.LookupIdent(tok.Literal) // Lookup the identifier in the dialect's keywords table
 			return tok
 		} else if isDigit(l.ch) {
 			tok.Literal = l.readNumber() // Read the number
 			tok.Type = token.INT
 			return tok
 		} else {
-			tok = newToken(token.ILLEGAL, l.ch)
+			tok.Literal = l.input[l.position:l.readPosition] // The raw bytes, so invalid UTF-8 is reported as written
+			tok.Type = token.ILLEGAL
 		}
 	}
 	l.readChar()
@@ -107,51 +303,121 @@ newToken creates a new token instance
 
 @param tokenType token.TokenType - Type of token (e.g. IDENT, INT, ASSIGN, etc.)
 
-@param ch byte - Character to be tokenized
+@param ch rune - Character to be tokenized
 
 @return token.Token - A new token instance
 */
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
-// readIdentifier reads an identifier and advances the position until it encounters a non-letter character
+/*
+newTwoCharToken creates a token from the current character and the one after it,
+advancing the lexer past the first of the two (NextToken advances past the second)
+
+@param tokenType token.TokenType - Type of token (e.g. EQ, NOT_EQ, AND, etc.)
+
+@return token.Token - A new token instance
+*/
+func (l *Lexer) newTwoCharToken(tokenType token.TokenType) token.Token {
+	ch := l.ch
+	l.readChar()
+	return token.Token{Type: tokenType, Literal: string(ch) + string(l.ch)}
+}
+
+/*
+readString reads string contents starting just after the current char (an opening quote, or the } closing an
+interpolation). It stops at the closing quote, or at ${ in which case the lexer switches to expression mode
+until the matching }. Escape sequences are decoded; \$ gives a literal dollar sign.
+
+@param open token.TokenType - Type of token to return when the string continues with ${
+
+@param closed token.TokenType - Type of token to return when the string ends with a closing quote
+
+@return token.Token - The string part, or token.ILLEGAL if the string is not terminated or has a bad escape
+*/
+func (l *Lexer) readString(open, closed token.TokenType) token.Token {
+	var out strings.Builder
+
+	for {
+		l.readChar()
+		switch {
+		case l.ch == 0 || l.ch == '\n':
+			return token.Token{Type: token.ILLEGAL, Literal: "\"" + out.String()} // Unterminated string
+		case l.ch == '"':
+			l.readChar() // Step past the closing quote
+			return token.Token{Type: closed, Literal: out.String()}
+		case l.ch == '$' && l.peakChar() == '{':
+			l.readChar()
+			l.readChar() // Step past ${
+			l.interpolations = append(l.interpolations, 0)
+			return token.Token{Type: open, Literal: out.String()}
+		case l.ch == '\\':
+			l.readChar()
+			escaped, ok := escapes[l.ch]
+			if !ok {
+				return token.Token{Type: token.ILLEGAL, Literal: "\\" + string(l.ch)} // Unknown escape sequence
+			}
+			out.WriteRune(escaped)
+		default:
+			out.WriteRune(l.ch)
+		}
+	}
+}
+
+// escapes maps the char after a backslash in a string to the char it stands for
+var escapes = map[rune]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'"':  '"',
+	'\\': '\\',
+	'$':  '$',
+}
+
+// readIdentifier reads an identifier and advances the position until it encounters a char that is neither a letter nor a digit
 func (l *Lexer) readIdentifier() string {
 	position := l.position // Save the current position
-	for isLetter(l.ch) {
+
+	// The first char is always a letter (NextToken checked it), digits are allowed after it
+	for isLetter(l.ch) || unicode.IsDigit(l.ch) {
 		l.readChar() // Read the next character
 	}
 	return l.input[position:l.position] // Return the identifier
 }
 
-// readNumber reads a number and advances the position until it encounters a non-digit character
+/*
+readNumber reads a number and advances the position past it. Letters written straight after the
+digits (e.g. the x in 0x1F) are read as part of the number, so the parser reports the literal as
+invalid instead of it being silently split into a number and an identifier.
+*/
 func (l *Lexer) readNumber() string {
 	position := l.position // Save the current position
-	for isDigit(l.ch) {
+	for isDigit(l.ch) || isLetter(l.ch) {
 		l.readChar() // Read the next character
 	}
 	return l.input[position:l.position] // Return the number
 }
 
 /*
-isLetter checks if a character is a letter
+isLetter checks if a character is a letter in any script (or an underscore)
 
-@param ch byte - Character to be checked
+@param ch rune - Character to be checked
 
 @return bool - True if the character is a letter, false otherwise
 */
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
 /*
-isDigit checks if a character is a digit
+isDigit checks if a character is an ASCII digit
 
-@param ch byte - Character to be checked
+@param ch rune - Character to be checked
 
 @return bool - True if the character is a digit, false otherwise
 */
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
@@ -164,15 +430,63 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
+/*
+atDocComment checks if the lexer is at the start of a /// doc comment.
+Four or more slashes are treated as an ordinary line comment.
+
+@return bool - True if the input at the current position starts a doc comment
+*/
+func (l *Lexer) atDocComment() bool {
+	rest := l.input[l.position:]
+	return strings.HasPrefix(rest, "///") && !strings.HasPrefix(rest, "////")
+}
+
+// skipLineComment skips a // comment up to (but not including) the end of the line
+func (l *Lexer) skipLineComment() {
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+}
+
+// skipBlockComment skips a block comment. Block comments nest, so every opening "/*" needs its own "*/".
+// It returns false if the input ended before the comment was closed.
+func (l *Lexer) skipBlockComment() bool {
+	depth := 0
+	for l.ch != 0 {
+		if l.ch == '/' && l.peakChar() == '*' {
+			depth++
+			l.readChar()
+		} else if l.ch == '*' && l.peakChar() == '/' {
+			depth--
+			l.readChar()
+			if depth == 0 {
+				l.readChar() // Step past the closing /
+				return true
+			}
+		}
+		l.readChar()
+	}
+	return false
+}
+
+// readDocComment reads a /// comment and returns its text without the slashes and the leading space
+func (l *Lexer) readDocComment() string {
+	position := l.position + len("///")
+	l.skipLineComment()
+	text := strings.TrimSuffix(l.input[position:l.position], "\r")
+	return strings.TrimPrefix(text, " ")
+}
+
 /*
 peakChar returns the next character in the input without advancing the position
 
-@return byte - The next character in the input
+@return rune - The next character in the input
 */
-func (l *Lexer) peakChar() byte {
+func (l *Lexer) peakChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0 // ASCII code for "NUL"
 	} else {
-		return l.input[l.readPosition] // Get the current character
+		ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:]) // Get the next character
+		return ch
 	}
 }
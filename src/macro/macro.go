@@ -0,0 +1,230 @@
+package macro
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+)
+
+// MaxExpansionDepth is how many times ExpandMacros goes over a program before it gives up on macros that keep producing calls to macros
+const MaxExpansionDepth = 100
+
+/*
+DefineMacros removes the macro definitions (let name = macro(...) { ... };) from the top
+level of a program and returns them. Macros defined anywhere else are left in place.
+
+@param program *ast.Program - The parsed program, modified in place
+
+@return map[string]*ast.MacroLiteral - The macros, by the name they were bound to
+*/
+func DefineMacros(program *ast.Program) map[string]*ast.MacroLiteral {
+	macros := map[string]*ast.MacroLiteral{}
+	statements := program.Statements[:0]
+
+	for _, stmt := range program.Statements {
+		if let, ok := stmt.(*ast.LetStatement); ok && let.Name != nil {
+			if lit, ok := let.Value.(*ast.MacroLiteral); ok {
+				macros[let.Name.Value] = lit
+				continue
+			}
+		}
+		statements = append(statements, stmt)
+	}
+
+	program.Statements = statements
+	return macros
+}
+
+/*
+ExpandMacros replaces every call to one of the macros by the AST the macro produces from
+the argument expressions. Arguments are expanded before the call that receives them, and
+macro calls in what a macro produces are expanded in turn, up to MaxExpansionDepth deep.
+
+Without an evaluator only template macros can be expanded: the body must be a single
+quote(...) whose unquote(...) calls each name a parameter of the macro. Bodies that
+compute their result need the macro body to be run and are reported as an error.
+
+@param program ast.Node - The program, usually after DefineMacros, modified in place
+
+@param macros map[string]*ast.MacroLiteral - The macros to expand, by name
+
+@return ast.Node - The expanded program
+
+@return error - The first call that could not be expanded, or the expansion not finishing
+*/
+func ExpandMacros(program ast.Node, macros map[string]*ast.MacroLiteral) (ast.Node, error) {
+	for depth := 0; depth < MaxExpansionDepth; depth++ {
+		expanded, changed, err := expandOnce(program, macros)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			return expanded, nil
+		}
+		program = expanded
+	}
+
+	if name := firstCall(program, macros); name != nil {
+		return nil, fmt.Errorf("macro %s is still expanding after %d rounds at %d:%d", name.Value, MaxExpansionDepth, name.Token.Line, name.Token.Column)
+	}
+	return program, nil
+}
+
+// expandOnce expands every macro call in the program once, and reports whether there were any
+func expandOnce(program ast.Node, macros map[string]*ast.MacroLiteral) (ast.Node, bool, error) {
+	var err error
+	changed := false
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if !ok || err != nil {
+			return node
+		}
+		ident, ok := call.Function.(*ast.Identifier)
+		if !ok {
+			return node
+		}
+		macro, ok := macros[ident.Value]
+		if !ok {
+			return node
+		}
+
+		var result ast.Node
+		result, err = expand(ident.Value, macro, call)
+		if err != nil {
+			err = fmt.Errorf("%s at %d:%d", err, ident.Token.Line, ident.Token.Column)
+			return node
+		}
+		changed = true
+		return result
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+	return expanded, changed, nil
+}
+
+// firstCall returns the name in a call to one of the macros in the program, nil if there is none
+func firstCall(program ast.Node, macros map[string]*ast.MacroLiteral) *ast.Identifier {
+	var found *ast.Identifier
+	ast.Modify(program, func(node ast.Node) ast.Node {
+		if call, ok := node.(*ast.CallExpression); ok && found == nil {
+			if ident, ok := call.Function.(*ast.Identifier); ok && macros[ident.Value] != nil {
+				found = ident
+			}
+		}
+		return node
+	})
+	return found
+}
+
+// expand returns the AST a template macro produces for one call
+func expand(name string, macro *ast.MacroLiteral, call *ast.CallExpression) (ast.Node, error) {
+	if len(call.Arguments) != len(macro.Parameters) {
+		return nil, fmt.Errorf("macro %s expects %d arguments, got %d", name, len(macro.Parameters), len(call.Arguments))
+	}
+
+	args := map[string]ast.Expression{}
+	for i, param := range macro.Parameters {
+		if _, ok := call.Arguments[i].(*ast.NamedArgument); ok {
+			return nil, fmt.Errorf("macro %s cannot take named arguments", name)
+		}
+		args[param.Value] = call.Arguments[i]
+	}
+
+	template := quoted(macro)
+	if template == nil {
+		return nil, fmt.Errorf("macro %s must have a single quote(...) as its body to be expanded without an evaluator", name)
+	}
+
+	var err error
+	result := ast.Modify(clone(template), func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if !ok || !isCallTo(call, "unquote") {
+			return node
+		}
+		if ident, ok := call.Arguments[0].(*ast.Identifier); ok {
+			if arg, ok := args[ident.Value]; ok {
+				return clone(arg) // Every splice gets its own copy so the result is a tree
+			}
+		}
+		if err == nil {
+			err = fmt.Errorf("macro %s: unquote(%s) needs the evaluator, only parameters can be unquoted", name, call.Arguments[0])
+		}
+		return node
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// quoted returns the expression inside the body of a macro of the form { quote(expr); }, nil if the body has another form
+func quoted(macro *ast.MacroLiteral) ast.Expression {
+	if len(macro.Body.Statements) != 1 {
+		return nil
+	}
+	stmt, ok := macro.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		return nil
+	}
+	call, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok || !isCallTo(call, "quote") {
+		return nil
+	}
+	return call.Arguments[0]
+}
+
+// isCallTo reports whether call is a call with one argument to the function with the given name
+func isCallTo(call *ast.CallExpression, name string) bool {
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == name && len(call.Arguments) == 1
+}
+
+// clone returns a deep copy of a node, so that modifying the copy leaves the original alone
+func clone(node ast.Node) ast.Node {
+	return deepCopy(reflect.ValueOf(node)).Interface().(ast.Node)
+}
+
+// deepCopy copies a value, following pointers, interfaces and slices
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Elem().Type())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v) // Unexported fields (e.g. inside library types) are copied as they are
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
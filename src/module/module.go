@@ -0,0 +1,199 @@
+package module
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/lexer"
+	"github.com/kriptonian1/BroLang/src/macro"
+	"github.com/kriptonian1/BroLang/src/parser"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Extension is the file extension of BroLang source files. It is added to import paths that leave it out.
+const Extension = ".bro"
+
+// Module is a parsed source file together with the modules it imports
+type Module struct {
+	Path    string             // Absolute path of the source file
+	Program *ast.Program       // The parsed source
+	Imports map[string]*Module // The imported modules, by the alias they were imported as
+	Exports []string           // The names declared with export, in source order
+}
+
+// Loader finds, parses and caches modules. Each file is loaded once, however often it is imported.
+type Loader struct {
+	SearchPath []string       // Directories searched for imports that are not relative (e.g. from BROPATH)
+	Dialect    *token.Dialect // The dialect of files without a dialect pragma, classic if nil
+
+	modules map[string]*Module // Loaded modules, by absolute path
+	loading []string           // Absolute paths of the modules being loaded, outermost first
+	root    string             // Directory of the first module loaded, used to shorten paths in errors
+}
+
+/*
+NewLoader creates a new loader
+
+@param searchPath []string - Directories searched, in order, for imports that are not relative
+
+@return *Loader - A new loader with an empty cache
+*/
+func NewLoader(searchPath []string) *Loader {
+	return &Loader{SearchPath: searchPath, modules: map[string]*Module{}}
+}
+
+/*
+SearchPathFromEnv returns the directories listed in the BROPATH environment variable,
+separated like PATH (: on Unix, ; on Windows)
+
+@return []string - The directories, empty if BROPATH is not set
+*/
+func SearchPathFromEnv() []string {
+	dirs := []string{}
+	for _, dir := range filepath.SplitList(os.Getenv("BROPATH")) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+/*
+Load loads the module at path and, recursively, every module it imports
+
+@param path string - Path of the source file, absolute or relative to the working directory
+
+@return *Module - The loaded module (the cached one if it was loaded before)
+
+@return error - If a file cannot be found, read or parsed, or the imports form a cycle
+*/
+func (l *Loader) Load(path string) (*Module, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if l.root == "" {
+		l.root = filepath.Dir(abs)
+	}
+	return l.load(abs)
+}
+
+// load loads the module at the absolute path abs, detecting import cycles on the way
+func (l *Loader) load(abs string) (*Module, error) {
+	for i, loading := range l.loading {
+		if loading == abs {
+			cycle := append(append([]string{}, l.loading[i:]...), abs)
+			return nil, fmt.Errorf("import cycle: %s", l.chain(cycle))
+		}
+	}
+
+	if mod, ok := l.modules[abs]; ok {
+		return mod, nil
+	}
+
+	source, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read module %s: %w", l.display(abs), err)
+	}
+
+	p := parser.New(lexer.New(string(source), lexer.WithDialect(l.Dialect)))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		msgs := []string{}
+		for _, e := range errs {
+			msgs = append(msgs, e.Error())
+		}
+		return nil, fmt.Errorf("%s: %s", l.display(abs), strings.Join(msgs, "; "))
+	}
+
+	expanded, err := macro.ExpandMacros(program, macro.DefineMacros(program)) // The checkers only ever see expanded code
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", l.display(abs), err)
+	}
+	program = expanded.(*ast.Program)
+
+	mod := &Module{Path: abs, Program: program, Imports: map[string]*Module{}}
+
+	l.loading = append(l.loading, abs)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+
+	for _, stmt := range program.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.ImportStatement:
+			target, err := l.resolve(filepath.Dir(abs), stmt.Path.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d:%d: %w", l.display(abs), stmt.Token.Line, stmt.Token.Column, err)
+			}
+			imported, err := l.load(target)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := mod.Imports[stmt.Alias.Value]; ok {
+				return nil, fmt.Errorf("%s:%d:%d: %s is already imported", l.display(abs), stmt.Token.Line, stmt.Token.Column, stmt.Alias.Value)
+			}
+			mod.Imports[stmt.Alias.Value] = imported
+		case *ast.ExportStatement:
+			mod.Exports = append(mod.Exports, stmt.Names()...)
+		}
+	}
+
+	l.modules[abs] = mod
+	return mod, nil
+}
+
+/*
+resolve turns an import path into the absolute path of a file. Paths starting with ./ or ../
+are relative to the importing file; other relative paths are looked up in each search path
+directory in turn. The .bro extension may be left out.
+
+@param dir string - Directory of the importing file
+
+@param spec string - The path as written in the import statement
+
+@return string - The absolute path of the file
+
+@return error - If no file matches
+*/
+func (l *Loader) resolve(dir, spec string) (string, error) {
+	var candidates []string
+	switch {
+	case filepath.IsAbs(spec):
+		candidates = []string{spec}
+	case strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../"):
+		candidates = []string{filepath.Join(dir, spec)}
+	default:
+		for _, searchDir := range l.SearchPath {
+			candidates = append(candidates, filepath.Join(searchDir, spec))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if filepath.Ext(candidate) != Extension {
+			candidate += Extension
+		}
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return filepath.Abs(candidate)
+		}
+	}
+	return "", fmt.Errorf("cannot find module %q", spec)
+}
+
+// chain formats a list of module paths as a -> b -> c
+func (l *Loader) chain(paths []string) string {
+	shown := []string{}
+	for _, path := range paths {
+		shown = append(shown, l.display(path))
+	}
+	return strings.Join(shown, " -> ")
+}
+
+// display shortens an absolute path to be relative to the first module loaded, when that is shorter
+func (l *Loader) display(abs string) string {
+	if rel, err := filepath.Rel(l.root, abs); err == nil && len(rel) < len(abs) {
+		return rel
+	}
+	return abs
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+It parses an expression in parentheses. The parentheses only group, and leave no node behind,
+unless they are followed by => which makes them the parameters of an arrow function:
+
+	(0..10).step(2)
+	(a, b) => a + b
+	() => 42
+*/
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	off := p.arrowsOff
+	p.arrowsOff = false
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		p.arrowsOff = off
+		if !p.expectPeek(token.FAT_ARROW) { // () is only valid as an empty parameter list
+			return nil
+		}
+		return p.parseArrowFunction(nil, true)
+	}
+
+	p.nextToken()
+	exps := []ast.Expression{p.parseExpression(LOWEST)}
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		exps = append(exps, p.parseExpression(LOWEST))
+	}
+	p.arrowsOff = off
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if len(exps) > 1 || (p.peekTokenIs(token.FAT_ARROW) && !p.arrowsOff) {
+		if !p.expectPeek(token.FAT_ARROW) { // (a, b) is only valid as a parameter list
+			return nil
+		}
+		return p.parseArrowFunction(exps, true)
+	}
+	return exps[0]
+}
+
+/*
+It parses the body of an arrow function once its parameters and the => have been read.
+The parameters were parsed as expressions, so they are checked to be plain names here;
+a function with typed or default parameters is written with fn instead.
+*/
+func (p *Parser) parseArrowFunction(params []ast.Expression, parenthesized bool) ast.Expression {
+	arrow := &ast.ArrowFunction{Token: p.curToken, Parameters: []*ast.Parameter{}, Parenthesized: parenthesized}
+	for _, param := range params {
+		ident, ok := param.(*ast.Identifier)
+		if !ok {
+			p.errorAtCurrent("the parameters of an arrow function must be names")
+			return nil
+		}
+		arrow.Parameters = append(arrow.Parameters, &ast.Parameter{Name: ident})
+	}
+
+	p.yields = append(p.yields, false)
+	p.nextToken()
+	arrow.Body = p.parseExpression(LOWEST)
+	generator := p.yields[len(p.yields)-1]
+	p.yields = p.yields[:len(p.yields)-1]
+
+	if generator {
+		msg := fmt.Sprintf("an arrow function cannot yield at %d:%d; write a generator with fn", arrow.Token.Line, arrow.Token.Column)
+		p.errors = append(p.errors, errors.New(msg))
+		return nil
+	}
+	if arrow.Body == nil {
+		return nil
+	}
+	ast.MarkTailCalls(arrow.Block())
+	return arrow
+}
+
+// Parses a pipeline (e.g. data |> filter(isValid)) once the left side and the |> have been read
+func (p *Parser) parsePipelineExpression(left ast.Expression) ast.Expression {
+	exp := &ast.PipelineExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	exp.Right = p.parseExpression(PIPELINE) // Left-associative: a |> f |> g is (a |> f) |> g
+	if exp.Right == nil {
+		return nil
+	}
+	return exp
+}
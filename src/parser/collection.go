@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Parses a spread (e.g. ...args) once the ... has been read
+func (p *Parser) parseSpreadElement() *ast.SpreadElement {
+	spread := &ast.SpreadElement{Token: p.curToken}
+
+	p.nextToken()
+	spread.Value = p.parseExpression(LOWEST)
+	if spread.Value == nil {
+		return nil
+	}
+	return spread
+}
+
+// Parses an array literal (e.g. [1, x, ...rest]); spreads can appear anywhere in it
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.curToken, Elements: []ast.Expression{}}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		var el ast.Expression
+		if p.curTokenIs(token.ELLIPSIS) {
+			if spread := p.parseSpreadElement(); spread != nil {
+				el = spread
+			}
+		} else {
+			el = p.parseExpression(LOWEST)
+		}
+		if el == nil {
+			return nil
+		}
+		array.Elements = append(array.Elements, el)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return array
+}
+
+// Parses a hash literal (e.g. {...defaults, "port": 8080}); spreads can appear anywhere in it
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken, Pairs: []*ast.HashPair{}}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		pair := &ast.HashPair{}
+		if p.curTokenIs(token.ELLIPSIS) {
+			if spread := p.parseSpreadElement(); spread != nil {
+				pair.Value = spread
+			}
+		} else {
+			pair = p.parseHashPair()
+		}
+		if pair == nil || pair.Value == nil {
+			return nil
+		}
+		hash.Pairs = append(hash.Pairs, pair)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return hash
+}
+
+/*
+Parses one key: value entry of a hash literal. Keys are written like in hash patterns: strings
+or integers ("port": 8080), or bare names which stand for the string of the same name (port: 8080).
+A bare name without a value stores the variable of that name ({port} is short for {port: port}).
+*/
+func (p *Parser) parseHashPair() *ast.HashPair {
+	pair := &ast.HashPair{}
+
+	switch p.curToken.Type {
+	case token.IDENT:
+		key := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		pair.Key = key
+		if !p.peekTokenIs(token.COLON) { // Shorthand
+			pair.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			return pair
+		}
+	case token.STRING, token.INT:
+		pair.Key = p.prefixParseFns[p.curToken.Type]()
+	default:
+		msg := fmt.Sprintf("expected a name, string or integer key in hash literal at %d:%d, got %s instead", p.curToken.Line, p.curToken.Column, p.curToken.Type)
+		p.errors = append(p.errors, errors.New(msg))
+		return nil
+	}
+
+	if pair.Key == nil || !p.expectPeek(token.COLON) {
+		return nil
+	}
+
+	p.nextToken()
+	pair.Value = p.parseExpression(LOWEST)
+	return pair
+}
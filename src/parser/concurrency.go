@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Parses a spawn expression (e.g. spawn check(url)); only a call can be spawned
+func (p *Parser) parseSpawnExpression() ast.Expression {
+	exp := &ast.SpawnExpression{Token: p.curToken}
+
+	p.nextToken()
+	call, ok := p.parseExpression(PREFIX).(*ast.CallExpression) // Binds like a prefix operator: spawn f(x) + 1 does not spawn the sum
+	if !ok {
+		msg := fmt.Sprintf("spawn needs a function call at %d:%d", exp.Token.Line, exp.Token.Column)
+		p.errors = append(p.errors, errors.New(msg))
+		return nil
+	}
+	exp.Call = call
+	return exp
+}
+
+/*
+It parses a select expression. Cases are separated by commas like match arms:
+
+	select {
+		recv(ch) as v => v,
+		send(out, x) => true,
+		_ => null,
+	}
+*/
+func (p *Parser) parseSelectExpression() ast.Expression {
+	exp := &ast.SelectExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	hasDefault := false
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		c := p.parseSelectCase()
+		if c == nil {
+			return nil
+		}
+		if c.Operation == nil {
+			if hasDefault {
+				p.errorAtCurrent("select has more than one default case")
+				return nil
+			}
+			hasDefault = true
+		}
+		exp.Cases = append(exp.Cases, c)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	if len(exp.Cases) == 0 {
+		p.errorAtCurrent("select needs at least one case")
+		return nil
+	}
+	return exp
+}
+
+// Parses one case of a select starting at its first token
+func (p *Parser) parseSelectCase() *ast.SelectCase {
+	c := &ast.SelectCase{Token: p.curToken}
+
+	if p.curTokenIs(token.IDENT) && p.curToken.Literal == "_" {
+		// The default case
+	} else if c.Operation = p.parseChannelOperation(); c.Operation == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.AS) {
+		if !isCallTo(c.Operation, "recv") {
+			p.errorAtCurrent("only a recv case can bind the received value with as")
+			return nil
+		}
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		c.Binding = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+	p.nextToken()
+	c.Body = p.parseExpression(LOWEST)
+	return c
+}
+
+// Parses the operation of a select case, which must be recv(ch) or send(ch, value)
+func (p *Parser) parseChannelOperation() *ast.CallExpression {
+	call, ok := p.parseExpression(LOWEST).(*ast.CallExpression)
+	if ok && ((isCallTo(call, "recv") && len(call.Arguments) == 1) || (isCallTo(call, "send") && len(call.Arguments) == 2)) {
+		return call
+	}
+	p.errorAtCurrent("a select case must be recv(ch), send(ch, value) or _")
+	return nil
+}
+
+// isCallTo reports whether call calls the function with the given name directly
+func isCallTo(call *ast.CallExpression, name string) bool {
+	if call == nil {
+		return false
+	}
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == name
+}
@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+It parses an enum declaration (e.g. enum Result { Ok(v), Err(e) } or enum State { Pending, Done }).
+Variant names must be unique across all enums of the program, because a bare variant name is
+its constructor and its pattern.
+*/
+func (p *Parser) parseEnumDecl() *ast.EnumDecl {
+	decl := &ast.EnumDecl{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	decl.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		variant := &ast.EnumVariant{Enum: decl.Name, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+
+		if existing, ok := p.variants[variant.Name.Value]; ok {
+			p.errorAtCurrent(fmt.Sprintf("variant %s is already declared by enum %s", variant.Name.Value, existing.Enum.Value))
+			return nil
+		}
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			variant.Fields = p.parseVariantFields()
+			if variant.Fields == nil {
+				return nil
+			}
+		}
+		decl.Variants = append(decl.Variants, variant)
+		p.variants[variant.Name.Value] = variant
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return decl
+}
+
+// Parses the field names of a variant up to and including the closing parenthesis (e.g. the (v) of Ok(v))
+func (p *Parser) parseVariantFields() []*ast.Identifier {
+	fields := []*ast.Identifier{}
+
+	for !p.peekTokenIs(token.RPAREN) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		fields = append(fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	return fields
+}
+
+/*
+It parses a variant pattern (e.g. Ok(v), Pending or Result.Err(_)). When the variant was
+declared earlier in the program, the qualifier and the number of values are checked against it;
+variants declared later are checked by resolveVariants once the whole program is parsed.
+*/
+func (p *Parser) parseVariantPattern() ast.Pattern {
+	pattern := &ast.VariantPattern{Token: p.curToken}
+	pattern.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.DOT) { // Qualified with the enum name
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		pattern.Enum = pattern.Name
+		pattern.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if p.peekTokenIs(token.LPAREN) {
+		p.nextToken()
+		for !p.peekTokenIs(token.RPAREN) {
+			p.nextToken()
+			arg := p.parsePattern()
+			if arg == nil {
+				return nil
+			}
+			pattern.Arguments = append(pattern.Arguments, arg)
+
+			if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+	}
+
+	if msg := p.variantMismatch(pattern); msg != "" {
+		p.errorAtCurrent(msg)
+		return nil
+	}
+	return pattern
+}
+
+// variantMismatch describes how a variant pattern does not fit its variant, empty if it fits or the variant is not declared
+func (p *Parser) variantMismatch(pattern *ast.VariantPattern) string {
+	variant, ok := p.variants[pattern.Name.Value]
+	switch {
+	case !ok:
+		return "" // Declared somewhere we cannot see, so there is nothing to check
+	case pattern.Enum != nil && pattern.Enum.Value != variant.Enum.Value:
+		return fmt.Sprintf("%s is a variant of %s, not %s", pattern.Name.Value, variant.Enum.Value, pattern.Enum.Value)
+	case len(pattern.Arguments) != len(variant.Fields):
+		return fmt.Sprintf("variant %s carries %d values, pattern has %d", pattern.Name.Value, len(variant.Fields), len(pattern.Arguments))
+	}
+	return ""
+}
+
+/*
+resolveVariants turns the bare names in match and let patterns that name a variant declared anywhere
+in the program into variant patterns, since an enum declared after a pattern (or exported) is not known
+while the pattern is parsed. Variant patterns parsed before their enum are checked against it here.
+Then every match is checked for arms that can never be reached.
+*/
+func (p *Parser) resolveVariants(program *ast.Program) {
+	ast.Modify(program, func(node ast.Node) ast.Node {
+		switch node := node.(type) {
+		case *ast.MatchExpression:
+			for _, arm := range node.Arms {
+				arm.Pattern = p.resolvePattern(arm.Pattern)
+			}
+			p.checkUnreachableArms(node)
+		case *ast.LetStatement:
+			if node.Pattern != nil {
+				node.Pattern = p.resolvePattern(node.Pattern)
+			}
+		}
+		return node
+	})
+}
+
+// resolvePattern returns the pattern with every binding of a variant's name replaced by that variant
+func (p *Parser) resolvePattern(pattern ast.Pattern) ast.Pattern {
+	switch pattern := pattern.(type) {
+	case *ast.BindingPattern:
+		if p.variants[pattern.Name.Value] != nil {
+			return p.resolvePattern(&ast.VariantPattern{Token: pattern.Token, Name: pattern.Name})
+		}
+	case *ast.VariantPattern:
+		for i, arg := range pattern.Arguments {
+			pattern.Arguments[i] = p.resolvePattern(arg)
+		}
+		if msg := p.variantMismatch(pattern); msg != "" {
+			p.errors = append(p.errors, fmt.Errorf("%s at %d:%d", msg, pattern.Name.Token.Line, pattern.Name.Token.Column))
+		}
+	case *ast.ArrayPattern:
+		for i, el := range pattern.Elements {
+			pattern.Elements[i] = p.resolvePattern(el)
+		}
+	case *ast.HashPattern:
+		for _, pair := range pattern.Pairs {
+			if binding, ok := pair.Value.(*ast.BindingPattern); ok && binding.Name == pair.Key { // The {name} shorthand always binds
+				continue
+			}
+			pair.Value = p.resolvePattern(pair.Value)
+		}
+	}
+	return pattern
+}
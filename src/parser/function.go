@@ -0,0 +1,246 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Parses a function literal (e.g. fn(x, y = 10, ...rest) { x + y; })
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	lit := &ast.FunctionLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	lit.Parameters = p.parseFunctionParameters()
+	if lit.Parameters == nil {
+		return nil
+	}
+
+	var ok bool
+	if lit.ReturnType, ok = p.parseOptionalType(token.ARROW); !ok {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body, lit.Generator = p.parseFunctionBody()
+
+	return lit
+}
+
+/*
+It parses the body of a function or method, starting at its {, and marks its tail calls.
+
+@return *ast.BlockStatement - The body
+
+@return bool - True if the body yields, which makes the function a generator
+*/
+func (p *Parser) parseFunctionBody() (*ast.BlockStatement, bool) {
+	p.yields = append(p.yields, false)
+	body := p.parseBlockStatement()
+	ast.MarkTailCalls(body)
+
+	generator := p.yields[len(p.yields)-1]
+	p.yields = p.yields[:len(p.yields)-1]
+	return body, generator
+}
+
+/*
+It parses a yield expression (e.g. yield x or a bare yield, which yields null) and marks
+the innermost function being parsed as a generator. Yields in nested functions belong to
+those functions only.
+*/
+func (p *Parser) parseYieldExpression() ast.Expression {
+	exp := &ast.YieldExpression{Token: p.curToken}
+
+	if len(p.yields) == 0 {
+		p.errorAtCurrent("yield outside a function")
+		return nil
+	}
+	p.yields[len(p.yields)-1] = true
+
+	switch p.peekToken.Type {
+	case token.SEMICOLON, token.RPAREN, token.RBRACE, token.RBRACKET, token.COMMA, token.EOF:
+		return exp
+	}
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST)
+	return exp
+}
+
+/*
+It parses a macro literal (e.g. macro(cond, body) { quote(...); }). Macros take
+their arguments as unevaluated AST, so parameters cannot have types or defaults, or be variadic.
+*/
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	lit := &ast.MacroLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	params := p.parseFunctionParameters()
+	if params == nil {
+		return nil
+	}
+	for _, param := range params {
+		if param.Default != nil || param.Variadic || param.Type != nil {
+			p.errorAtCurrent(fmt.Sprintf("macro parameter %s cannot have a type or a default, or be variadic", param.Name.Value))
+			return nil
+		}
+		lit.Parameters = append(lit.Parameters, param.Name)
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body = p.parseBlockStatement()
+
+	return lit
+}
+
+/*
+It parses the parameter list after fn( up to and including the closing parenthesis.
+Required parameters come first, then parameters with defaults, then at most one
+variadic parameter, which must be last.
+
+@return []*ast.Parameter - The parameters (empty, not nil, for fn()), or nil if the list is invalid
+*/
+func (p *Parser) parseFunctionParameters() []*ast.Parameter {
+	params := []*ast.Parameter{}
+	seenDefault := false
+
+	for !p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		param := &ast.Parameter{}
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			param.Variadic = true
+			p.nextToken()
+		}
+		if !p.curTokenIs(token.IDENT) {
+			p.errorAtCurrent(fmt.Sprintf("expected a parameter name, got %s instead", p.curToken.Type))
+			return nil
+		}
+		param.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		var ok bool
+		if param.Type, ok = p.parseOptionalType(token.COLON); !ok {
+			return nil
+		}
+
+		switch {
+		case param.Variadic && !p.peekTokenIs(token.RPAREN):
+			p.errorAtCurrent(fmt.Sprintf("variadic parameter %s must be the last parameter", param.Name.Value))
+			return nil
+		case p.peekTokenIs(token.ASSIGN):
+			p.nextToken()
+			p.nextToken()
+			param.Default = p.parseExpression(LOWEST)
+			seenDefault = true
+		case seenDefault && !param.Variadic:
+			p.errorAtCurrent(fmt.Sprintf("parameter %s without a default follows a parameter with one", param.Name.Value))
+			return nil
+		}
+		params = append(params, param)
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	return params
+}
+
+// Parses the statements between { and } (e.g. the body of a function)
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken}
+	block.Statements = []ast.Statement{}
+
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	return block
+}
+
+// Parses a call expression once the function and the ( have been read (e.g. add(1, y: 2))
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	exp := &ast.CallExpression{Token: p.curToken, Function: function}
+	off := p.arrowsOff
+	p.arrowsOff = false // Arguments are in parentheses, so any(xs, x => x > 0) works in a match guard
+	exp.Arguments = p.parseCallArguments()
+	p.arrowsOff = off
+	if exp.Arguments == nil {
+		return nil
+	}
+	return exp
+}
+
+/*
+It parses the arguments of a call up to and including the closing parenthesis.
+An argument written as name: value is passed by name; positional arguments
+cannot follow named ones.
+
+@return []ast.Expression - The arguments (empty, not nil, for f()), or nil if the list is invalid
+*/
+func (p *Parser) parseCallArguments() []ast.Expression {
+	args := []ast.Expression{}
+	seenNamed := false
+
+	for !p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+
+		if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.COLON) {
+			arg := &ast.NamedArgument{Token: p.curToken, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+			p.nextToken()
+			p.nextToken()
+			arg.Value = p.parseExpression(LOWEST)
+			if arg.Value == nil { // parseExpression has already reported why
+				return nil
+			}
+			args = append(args, arg)
+			seenNamed = true
+		} else if seenNamed {
+			p.errorAtCurrent("positional argument follows a named argument")
+			return nil
+		} else if p.curTokenIs(token.ELLIPSIS) { // f(...args) passes the elements of args as positional arguments
+			spread := p.parseSpreadElement()
+			if spread == nil {
+				return nil
+			}
+			args = append(args, spread)
+		} else {
+			arg := p.parseExpression(LOWEST)
+			if arg == nil {
+				return nil
+			}
+			args = append(args, arg)
+		}
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	return args
+}
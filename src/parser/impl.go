@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Parses a trait declaration (e.g. trait Shape { fn area(self) })
+func (p *Parser) parseTraitDecl() *ast.TraitDecl {
+	decl := &ast.TraitDecl{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	decl.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	decl.Methods = p.parseMethods(true)
+	if decl.Methods == nil {
+		return nil
+	}
+	return decl
+}
+
+// Parses an impl block (e.g. impl Point { ... } or impl Shape for Point { ... })
+func (p *Parser) parseImplDecl() *ast.ImplDecl {
+	decl := &ast.ImplDecl{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	decl.Type = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.FOR) { // What we read was the trait, the type comes next
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		decl.Trait = decl.Type
+		decl.Type = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	decl.Methods = p.parseMethods(false)
+	if decl.Methods == nil {
+		return nil
+	}
+	return decl
+}
+
+/*
+It parses the { fn ... } block of a trait or impl. Method names must be unique.
+
+@param inTrait bool - True if methods may leave out their body
+
+@return []*ast.MethodDecl - The methods (empty, not nil, for {}), or nil if the block is invalid
+*/
+func (p *Parser) parseMethods(inTrait bool) []*ast.MethodDecl {
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	methods := []*ast.MethodDecl{}
+	seen := map[string]bool{}
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.FUNCTION) {
+			return nil
+		}
+		method := &ast.MethodDecl{Token: p.curToken}
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		method.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[method.Name.Value] {
+			p.errorAtCurrent(fmt.Sprintf("duplicate method %s", method.Name.Value))
+			return nil
+		}
+		seen[method.Name.Value] = true
+
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+		method.Parameters = p.parseFunctionParameters()
+		if method.Parameters == nil {
+			return nil
+		}
+
+		var ok bool
+		if method.ReturnType, ok = p.parseOptionalType(token.ARROW); !ok {
+			return nil
+		}
+
+		if p.peekTokenIs(token.LBRACE) {
+			p.nextToken()
+			method.Body, method.Generator = p.parseFunctionBody()
+		} else if !inTrait {
+			p.peekError(token.LBRACE)
+			return nil
+		}
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+
+		methods = append(methods, method)
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return methods
+}
+
+/*
+checkImpls records an error for every impl Trait for Type in the program that does not
+satisfy its trait: the trait must exist, every trait method without a default must be
+implemented with the same number of parameters, and no other methods may be added. A trait
+that is not declared in the program may come from an imported module, which the parser cannot
+see, so impls of it are only checked when the program imports nothing.
+*/
+func (p *Parser) checkImpls(program *ast.Program) {
+	traits := map[string]*ast.TraitDecl{}
+	imports := false
+	for _, stmt := range program.Statements {
+		if _, ok := stmt.(*ast.ImportStatement); ok {
+			imports = true
+		}
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = export.Declaration
+		}
+		if trait, ok := stmt.(*ast.TraitDecl); ok {
+			traits[trait.Name.Value] = trait
+		}
+	}
+
+	for _, stmt := range program.Statements {
+		impl, ok := stmt.(*ast.ImplDecl)
+		if !ok || impl.Trait == nil {
+			continue
+		}
+		where := fmt.Sprintf("impl %s for %s at %d:%d", impl.Trait.Value, impl.Type.Value, impl.Token.Line, impl.Token.Column)
+
+		trait, ok := traits[impl.Trait.Value]
+		if !ok && imports {
+			continue // Possibly exported by an imported module
+		}
+		if !ok {
+			p.errors = append(p.errors, fmt.Errorf("%s: unknown trait %s", where, impl.Trait.Value))
+			continue
+		}
+
+		provided := map[string]*ast.MethodDecl{}
+		for _, m := range impl.Methods {
+			provided[m.Name.Value] = m
+		}
+
+		required := map[string]bool{}
+		for _, want := range trait.Methods {
+			required[want.Name.Value] = true
+			got, ok := provided[want.Name.Value]
+			switch {
+			case !ok && want.Body == nil:
+				p.errors = append(p.errors, fmt.Errorf("%s: missing method %s", where, want.Name.Value))
+			case ok && len(got.Parameters) != len(want.Parameters):
+				p.errors = append(p.errors, fmt.Errorf("%s: method %s takes %d parameters, trait %s declares %d",
+					where, want.Name.Value, len(got.Parameters), trait.Name.Value, len(want.Parameters)))
+			}
+		}
+
+		for _, m := range impl.Methods {
+			if !required[m.Name.Value] {
+				p.errors = append(p.errors, fmt.Errorf("%s: method %s is not part of trait %s", where, m.Name.Value, trait.Name.Value))
+			}
+		}
+	}
+}
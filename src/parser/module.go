@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Parses an import statement (e.g. import "./utils.bro" as utils;)
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+	stmt.Path = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.AS) || !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// Parses an export statement, which must wrap a let, struct, enum or trait declaration
+func (p *Parser) parseExportStatement() *ast.ExportStatement {
+	stmt := &ast.ExportStatement{Token: p.curToken}
+
+	switch p.peekToken.Type {
+	case token.LET, token.STRUCT, token.ENUM, token.TRAIT:
+		docs := p.curDocs
+		p.nextToken()
+		p.curDocs = docs // A doc comment above export documents the declaration
+		stmt.Declaration = p.parseStatement()
+		if stmt.Declaration == nil {
+			return nil
+		}
+		return stmt
+	default:
+		p.nextToken()
+		p.errorAtCurrent("export must be followed by let, struct, enum or trait")
+		return nil
+	}
+}
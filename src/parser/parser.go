@@ -3,8 +3,10 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/integer"
 	"github.com/kriptonian1/BroLang/src/lexer"
 	"github.com/kriptonian1/BroLang/src/token"
 )
@@ -12,20 +14,82 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // p.x = 1 (right-associative)
+	PIPELINE    // |>
+	COALESCE    // ??
+	LOGICAL_OR  // ||
+	LOGICAL_AND // &&
 	EQUALS      // ==
-	LESSGREATER // > or <
-	SUM         // +
-	PRODUCT     // *
-	PREFIX      // -X or !X
+	LESSGREATER // > or < or <= or >=
+	RANGE       // 0..n
+	SUM         // + or - or | or ^
+	PRODUCT     // * or / or & or << or >>
+	POWER       // ** (right-associative)
+	PREFIX      // -X or !X or ~X, though ** in X is applied first
 	CALL        // myFunction(X)
+	INDEX       // array[index]
+	MEMBER      // object.field or a?.b or a?.[index]
 )
 
+// MODULO shares its level with PRODUCT so that a * b % c groups left to right
+const MODULO = PRODUCT
+
+// Bitwise operators follow Go's ordering: | and ^ bind like +, while & and the shifts bind like *
+const (
+	BITWISE_OR  = SUM
+	BITWISE_AND = PRODUCT
+	SHIFT       = PRODUCT
+)
+
+// precedences maps infix operator tokens to their binding power
+var precedences = map[token.TokenType]int{
+	token.ASSIGN:   ASSIGN,
+	token.PIPE:     PIPELINE,
+	token.NULLISH:  COALESCE,
+	token.OR:       LOGICAL_OR,
+	token.AND:      LOGICAL_AND,
+	token.EQ:       EQUALS,
+	token.NOT_EQ:   EQUALS,
+	token.LT:       LESSGREATER,
+	token.GT:       LESSGREATER,
+	token.LT_EQ:    LESSGREATER,
+	token.GT_EQ:    LESSGREATER,
+	token.DOT_DOT:  RANGE,
+	token.PLUS:     SUM,
+	token.MINUS:    SUM,
+	token.SLASH:    PRODUCT,
+	token.ASTERISK: PRODUCT,
+	token.PERCENT:  MODULO,
+	token.POWER:    POWER,
+
+	token.BIT_OR:      BITWISE_OR,
+	token.BIT_XOR:     BITWISE_OR,
+	token.BIT_AND:     BITWISE_AND,
+	token.SHIFT_LEFT:  SHIFT,
+	token.SHIFT_RIGHT: SHIFT,
+
+	token.LPAREN:         CALL,
+	token.LBRACE:         CALL, // Point{x: 1}
+	token.LBRACKET:       INDEX,
+	token.DOT:            MEMBER,
+	token.OPTIONAL_CHAIN: MEMBER,
+}
+
 type Parser struct {
 	l *lexer.Lexer
 
 	curToken  token.Token // Current token
 	peekToken token.Token // Next token
 	errors    []error     // Errors
+	warnings  []error     // Problems that do not stop the program from running (e.g. a non-exhaustive match)
+
+	curDocs  []string // Doc comment lines that came right before the current token
+	peekDocs []string // Doc comment lines that came right before the next token
+
+	variants map[string]*ast.EnumVariant // Enum variants declared so far, by name, so patterns can recognize them
+	yields   []bool                      // One entry per function body being parsed, innermost last, true once it contains a yield
+
+	arrowsOff bool // True while a match guard is parsed outside any parentheses, where name => starts the arm body instead of an arrow function
 
 	prefixParseFns map[token.TokenType]prefixParseFn // Prefix parse functions
 	infixParseFns  map[token.TokenType]infixParseFn  // Infix parse functions
@@ -38,12 +102,49 @@ type (
 
 func New(l *lexer.Lexer) *Parser { // Creates a new parser
 	p := &Parser{
-		l:      l,
-		errors: []error{},
+		l:        l,
+		errors:   []error{},
+		warnings: []error{},
+		variants: map[string]*ast.EnumVariant{},
+	}
+	if err := l.Err(); err != nil { // A bad dialect pragma
+		p.errors = append(p.errors, err)
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn) // Creates a new map of prefix parse functions
 	p.registerPrefix(token.IDENT, p.parseIdentifier)           // Registers the identifier parse function to the map of prefix parse functions
+	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.INTERP_HEAD, p.parseInterpolatedString)
+	p.registerPrefix(token.TRUE, p.parseBoolean)
+	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNullLiteral)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
+	p.registerPrefix(token.YIELD, p.parseYieldExpression)
+	p.registerPrefix(token.SPAWN, p.parseSpawnExpression)
+	p.registerPrefix(token.SELECT, p.parseSelectExpression)
+	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.BANG, p.parsePrefixExpression)
+	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.BIT_NOT, p.parsePrefixExpression)
+
+	p.infixParseFns = make(map[token.TokenType]infixParseFn) // Creates a new map of infix parse functions
+	for tokenType := range precedences {                     // Every operator with a precedence is a binary infix operator...
+		p.registerInfix(tokenType, p.parseInfixExpression)
+	}
+	p.registerInfix(token.LPAREN, p.parseCallExpression) // ...except the postfix forms
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT_DOT, p.parseRangeExpression)
+	p.registerInfix(token.PIPE, p.parsePipelineExpression)
+	p.registerInfix(token.OPTIONAL_CHAIN, p.parseOptionalChain)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
+	p.registerInfix(token.LBRACE, p.parseStructLiteral)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
 
 	// Read two tokens so curToken and peekToken are both set
 	p.nextToken()
@@ -57,16 +158,189 @@ It creates a new identifier expression and sets the token to the current token.
 It sets the value to the literal value of the token. It returns the identifier expression.
 */
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.peekTokenIs(token.FAT_ARROW) && !p.arrowsOff { // x => x * 2
+		p.nextToken()
+		return p.parseArrowFunction([]ast.Expression{ident}, false)
+	}
+	return ident
+}
+
+/*
+It parses the literal of the current token as an integer of any length. The value is kept
+in Value while it fits in an int64 and in Big otherwise.
+*/
+func (p *Parser) parseIntegerLiteral() ast.Expression {
+	lit := &ast.IntegerLiteral{Token: p.curToken}
+
+	value, ok := integer.Parse(p.curToken.Literal) // Literals of any length, too long ones are kept as big integers
+	if !ok {
+		p.errorAtCurrent(fmt.Sprintf("could not parse %q as integer", p.curToken.Literal))
+		return nil
+	}
+
+	if small, ok := value.Int64(); ok {
+		lit.Value = small
+	} else {
+		lit.Big = value.Big()
+	}
+	return lit
+}
+
+// Parses a string literal without any ${...} parts
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+}
+
+/*
+It parses a string with ${...} parts. The lexer hands us the text around the
+embedded expressions as INTERP_HEAD, INTERP_MIDDLE and INTERP_TAIL tokens, with
+ordinary tokens for each expression in between.
+*/
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.curToken, Literals: []string{p.curToken.Literal}}
+
+	for {
+		if p.peekTokenIs(token.INTERP_MIDDLE) || p.peekTokenIs(token.INTERP_TAIL) {
+			msg := fmt.Sprintf("empty ${} in string at %d:%d", p.peekToken.Line, p.peekToken.Column)
+			p.errors = append(p.errors, errors.New(msg))
+			return nil
+		}
+
+		p.nextToken()
+		exp := p.parseExpression(LOWEST)
+		if exp == nil { // parseExpression has already reported why
+			return nil
+		}
+		str.Expressions = append(str.Expressions, exp)
+
+		switch {
+		case p.peekTokenIs(token.INTERP_MIDDLE):
+			p.nextToken()
+			str.Literals = append(str.Literals, p.curToken.Literal)
+		case p.expectPeek(token.INTERP_TAIL):
+			str.Literals = append(str.Literals, p.curToken.Literal)
+			return str
+		default:
+			return nil
+		}
+	}
+}
+
+// Parses the true and false keywords
+func (p *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
+}
+
+// Parses the null keyword
+func (p *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
+/*
+It parses an index expression (e.g. array[1]) once the left side and the [ have been read.
+A colon inside the brackets makes it a slice instead (e.g. array[1:3]), see parseSlice.
+*/
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	if p.curTokenIs(token.COLON) { // array[:n]
+		return p.parseSlice(exp.Token, left, nil)
+	}
+	exp.Index = p.parseExpression(LOWEST)
+	if exp.Index == nil { // parseExpression has already reported why
+		return nil
+	}
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		return p.parseSlice(exp.Token, left, exp.Index)
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return exp
+}
+
+/*
+It parses the null-safe access that follows ?. which is either a member name (a?.b)
+or a bracketed index (a?.[i]). Either way the node is marked Optional so that a null
+left side short-circuits instead of failing.
+*/
+func (p *Parser) parseOptionalChain(left ast.Expression) ast.Expression {
+	switch {
+	case p.peekTokenIs(token.IDENT):
+		exp := &ast.MemberExpression{Token: p.curToken, Object: left, Optional: true}
+		p.nextToken()
+		exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		return exp
+	case p.peekTokenIs(token.LBRACKET):
+		optional := p.curToken
+		p.nextToken()
+		switch exp := p.parseIndexExpression(left).(type) {
+		case *ast.IndexExpression:
+			exp.Token = optional
+			exp.Optional = true
+			return exp
+		case *ast.SliceExpression:
+			exp.Token = optional
+			exp.Optional = true
+			return exp
+		default:
+			return nil
+		}
+	default:
+		msg := fmt.Sprintf("expected a name or [ after ?., got %s instead", p.peekToken.Type)
+		p.errors = append(p.errors, errors.New(msg))
+		return nil
+	}
+}
+
+/*
+Parses a prefix operator expression (e.g. ~x, -1 or !ok). The operand binds tighter than any
+binary operator except **, so -2 ** 2 is -(2 ** 2) like in maths, while -a * b is (-a) * b.
+*/
+func (p *Parser) parsePrefixExpression() ast.Expression {
+	expression := &ast.PrefixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+	}
+
+	p.nextToken()
+	expression.Right = p.parseExpression(POWER - 1)
+	if expression.Right == nil {
+		return nil
+	}
+
+	return expression
 }
 
 func (p *Parser) Errors() []error { // Returns the errors
 	return p.errors
 }
 
-func (p *Parser) nextToken() { // Advances the tokens
+func (p *Parser) Warnings() []error { // Returns the warnings
+	return p.warnings
+}
+
+/*
+Advances the tokens. Doc comments never reach the grammar: they are collected
+here and remembered for the token they precede, so a declaration can pick them up.
+*/
+func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
+	p.curDocs = p.peekDocs
+	if p.curToken.Type == token.ILLEGAL { // Reported once here, wherever the parser happens to be
+		p.illegalToken()
+	}
+
+	p.peekDocs = nil
 	p.peekToken = p.l.NextToken()
+	for p.peekToken.Type == token.DOC_COMMENT {
+		p.peekDocs = append(p.peekDocs, p.peekToken.Literal)
+		p.peekToken = p.l.NextToken()
+	}
 }
 
 func (p *Parser) ParseProgram() *ast.Program { // Parses the program
@@ -81,15 +355,59 @@ func (p *Parser) ParseProgram() *ast.Program { // Parses the program
 		p.nextToken()
 
 	}
+
+	p.resolveVariants(program) // Needs every enum, for the same reason
+	p.checkImpls(program)      // Needs every trait, so it runs once the whole program is parsed
 	return program
 }
 
 func (p *Parser) parseStatement() ast.Statement { // Parses the statement
 	switch p.curToken.Type {
 	case token.LET: // If the token is a let token
-		return p.parseLetStatement() // Parses the let statement
+		if stmt := p.parseLetStatement(); stmt != nil { // Parses the let statement
+			return stmt
+		}
+		return nil // A nil *ast.LetStatement would otherwise become a non-nil ast.Statement
+	case token.STRUCT:
+		if stmt := p.parseStructDecl(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.ENUM:
+		if stmt := p.parseEnumDecl(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.TRAIT:
+		if stmt := p.parseTraitDecl(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.IMPL:
+		if stmt := p.parseImplDecl(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.IMPORT:
+		if stmt := p.parseImportStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.EXPORT:
+		if stmt := p.parseExportStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.THROW:
+		if stmt := p.parseThrowStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	case token.RETURN: // If the token is a return token
-		return p.parseReturnStatement() // Parses the return statement
+		if stmt := p.parseReturnStatement(); stmt != nil { // Parses the return statement
+			return stmt
+		}
+		return nil
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -97,19 +415,40 @@ func (p *Parser) parseStatement() ast.Statement { // Parses the statement
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken} // Creates a new let statement
+	if len(p.curDocs) > 0 {
+		stmt.Doc = strings.Join(p.curDocs, "\n") // Attaches the doc comment written above the let
+	}
 
-	if !p.expectPeek(token.IDENT) { // Checks if the next token is an identifier
+	switch {
+	case p.peekTokenIs(token.LBRACKET), p.peekTokenIs(token.LBRACE): // Destructuring (e.g. let [a, b] = arr;)
+		p.nextToken()
+		stmt.Pattern = p.parsePattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	case p.expectPeek(token.IDENT): // Checks if the next token is an identifier
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal} // Sets the name of the identifier
+	default:
 		return nil
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal} // Sets the name of the identifier
+	var ok bool
+	if stmt.Type, ok = p.parseOptionalType(token.COLON); !ok { // let x: int = 5;
+		return nil
+	}
 
 	if !p.expectPeek(token.ASSIGN) { // Checks if the next token is an assign token
 		return nil
 	}
 
-	//TODO: We're skipping the expressions until we encounter a semicolon
-	for !p.curTokenIs(token.SEMICOLON) { // Loops until the token is a semicolon
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST) // Parses the value of the variable
+
+	if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Name != nil {
+		fn.Name = stmt.Name.Value // Lets error messages refer to the function by name
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
@@ -119,10 +458,17 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken} // Creates a new return statement
 
+	if p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.RBRACE) { // A bare return has no value
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+		return stmt
+	}
+
 	p.nextToken()
+	stmt.ReturnValue = p.parseExpression(LOWEST) // Parses the returned value
 
-	//TODO: We're skipping the expressions until we encounter a semicolon
-	for !p.curTokenIs(token.SEMICOLON) { // Loops until the token is a semicolon
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
@@ -144,6 +490,26 @@ func (p *Parser) peekError(t token.TokenType) {
 	p.errors = append(p.errors, err)
 }
 
+// Records an error located at the current token
+func (p *Parser) errorAtCurrent(msg string) {
+	msg = fmt.Sprintf("%s at %d:%d", msg, p.curToken.Line, p.curToken.Column)
+	p.errors = append(p.errors, errors.New(msg))
+}
+
+// illegalToken records an error for the current token, which the lexer could not make sense of
+func (p *Parser) illegalToken() {
+	switch lit := p.curToken.Literal; {
+	case lit == "/*":
+		p.errorAtCurrent("unterminated block comment")
+	case strings.HasPrefix(lit, "\""):
+		p.errorAtCurrent("unterminated string")
+	case strings.HasPrefix(lit, "\\"):
+		p.errorAtCurrent(fmt.Sprintf("unknown escape sequence %s", lit))
+	default:
+		p.errorAtCurrent(fmt.Sprintf("illegal token %q", lit))
+	}
+}
+
 func (p *Parser) expectPeek(t token.TokenType) bool {
 	if p.peekTokenIs(t) {
 		p.nextToken()
@@ -174,8 +540,69 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefix := p.prefixParseFns[p.curToken.Type]
 	if prefix == nil {
+		if !p.curTokenIs(token.ILLEGAL) { // Already reported by nextToken
+			p.errorAtCurrent(fmt.Sprintf("no prefix parse function for %s", p.curToken.Type))
+		}
 		return nil
 	}
 	leftExp := prefix()
+	if leftExp == nil { // The prefix already failed, so there is nothing for an operator to apply to
+		return nil
+	}
+
+	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() { // Keeps folding infix operators that bind tighter than the caller
+		infix := p.infixParseFns[p.peekToken.Type]
+		if infix == nil {
+			return leftExp
+		}
+		p.nextToken()
+		leftExp = infix(leftExp)
+		if leftExp == nil { // The operator already failed, so the next one has nothing to apply to
+			return nil
+		}
+	}
 	return leftExp
 }
+
+/*
+parseInfixExpression parses a binary expression whose left operand has already been parsed.
+The ** operator is right-associative, so its right operand is parsed one level lower
+which lets a ** b ** c group as a ** (b ** c).
+
+@param left ast.Expression - The already parsed left operand
+
+@return ast.Expression - The infix expression
+*/
+func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+
+	precedence := p.curPrecedence()
+	if p.curTokenIs(token.POWER) {
+		precedence-- // Right-associative
+	}
+	p.nextToken()
+	expression.Right = p.parseExpression(precedence)
+	if expression.Right == nil { // The error is already recorded, and a nil operand would crash String
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) peekPrecedence() int { // Returns the precedence of the next token
+	if p, ok := precedences[p.peekToken.Type]; ok {
+		return p
+	}
+	return LOWEST
+}
+
+func (p *Parser) curPrecedence() int { // Returns the precedence of the current token
+	if p, ok := precedences[p.curToken.Type]; ok {
+		return p
+	}
+	return LOWEST
+}
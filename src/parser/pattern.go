@@ -0,0 +1,304 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/integer"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+It parses a match expression. Arms are separated by commas and a trailing comma is allowed:
+
+	match (value) {
+		0 => "zero",
+		[a, b] if a > b => a,
+		{"type": "x", ...rest} => rest,
+		_ => "other",
+	}
+*/
+func (p *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	exp.Subject = p.parseExpression(LOWEST)
+	if exp.Subject == nil { // parseExpression has already reported why
+		return nil
+	}
+
+	if !p.expectPeek(token.RPAREN) || !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		exp.Arms = append(exp.Arms, arm)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	p.checkBooleanExhaustive(exp)
+	return exp
+}
+
+// Parses one arm of a match expression (pattern [if guard] => body) starting at the pattern
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{Token: p.curToken}
+
+	arm.Pattern = p.parsePattern()
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		off := p.arrowsOff
+		p.arrowsOff = true // In n if ok => n, ok => n is not an arrow function
+		arm.Guard = p.parseExpression(LOWEST)
+		p.arrowsOff = off
+		if arm.Guard == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+	p.nextToken()
+	arm.Body = p.parseExpression(LOWEST)
+	if arm.Body == nil {
+		return nil
+	}
+
+	return arm
+}
+
+/*
+checkBooleanExhaustive records a warning when the arms of a match test for true
+or for false, but not both, and there is no unguarded arm that matches anything else.
+*/
+func (p *Parser) checkBooleanExhaustive(exp *ast.MatchExpression) {
+	seen := map[bool]bool{}
+
+	for _, arm := range exp.Arms {
+		if arm.Guard != nil { // A guarded arm may not be taken, so it covers nothing
+			continue
+		}
+		switch pattern := arm.Pattern.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+			return // Catch-all arm
+		case *ast.LiteralPattern:
+			if b, ok := pattern.Value.(*ast.Boolean); ok {
+				seen[b.Value] = true
+			}
+		}
+	}
+
+	if len(seen) == 1 {
+		missing := "true"
+		if seen[true] {
+			missing = "false"
+		}
+		msg := fmt.Sprintf("match at %d:%d is not exhaustive: missing %s", exp.Token.Line, exp.Token.Column, missing)
+		p.warnings = append(p.warnings, errors.New(msg))
+	}
+}
+
+/*
+checkUnreachableArms records a warning when an unguarded arm that matches any value (_ or a bare
+name) is followed by more arms, which can then never be taken. A bare name meant as a variant of
+an enum the parser cannot see (e.g. an imported one) binds the value instead, and this is how it shows.
+*/
+func (p *Parser) checkUnreachableArms(exp *ast.MatchExpression) {
+	for i := 0; i+1 < len(exp.Arms); i++ { // The last arm has nothing after it to hide
+		arm := exp.Arms[i]
+		if arm.Guard != nil {
+			continue
+		}
+		switch arm.Pattern.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+			next := exp.Arms[i+1].Token
+			msg := fmt.Sprintf("match arm at %d:%d is unreachable: %s at %d:%d matches every value", next.Line, next.Column, arm.Pattern.String(), arm.Token.Line, arm.Token.Column)
+			p.warnings = append(p.warnings, errors.New(msg))
+			return
+		}
+	}
+}
+
+// Parses the pattern starting at the current token
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.IDENT:
+		if p.curToken.Literal == "_" {
+			return &ast.WildcardPattern{Token: p.curToken}
+		}
+		if p.peekTokenIs(token.LPAREN) || p.peekTokenIs(token.DOT) || p.variants[p.curToken.Literal] != nil {
+			return p.parseVariantPattern()
+		}
+		return &ast.BindingPattern{Token: p.curToken, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+	case token.INT, token.STRING, token.TRUE, token.FALSE, token.NULL:
+		lit := &ast.LiteralPattern{Token: p.curToken, Value: p.prefixParseFns[p.curToken.Type]()}
+		if lit.Value == nil {
+			return nil
+		}
+		return lit
+	case token.MINUS:
+		if p.peekTokenIs(token.INT) {
+			return p.parseNegativeLiteralPattern()
+		}
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	}
+	msg := fmt.Sprintf("unexpected %s in pattern at %d:%d", p.curToken.Type, p.curToken.Line, p.curToken.Column)
+	p.errors = append(p.errors, errors.New(msg))
+	return nil
+}
+
+// Parses a negative integer pattern (e.g. the -1 in -1 => "minus one") into one literal starting at the -
+func (p *Parser) parseNegativeLiteralPattern() ast.Pattern {
+	minus := p.curToken
+	p.nextToken()
+	lit, ok := p.parseIntegerLiteral().(*ast.IntegerLiteral)
+	if !ok {
+		return nil
+	}
+
+	value := integer.New(lit.Value)
+	if lit.Big != nil {
+		value = integer.FromBig(lit.Big)
+	}
+	value = value.Neg()
+	lit.Value, lit.Big = 0, nil
+	if small, ok := value.Int64(); ok {
+		lit.Value = small
+	} else {
+		lit.Big = value.Big()
+	}
+	lit.Token = token.Token{Type: token.INT, Literal: "-" + lit.Token.Literal, Line: minus.Line, Column: minus.Column, Offset: minus.Offset}
+
+	return &ast.LiteralPattern{Token: minus, Value: lit}
+}
+
+// Parses an array pattern (e.g. [a, b, ...rest]); a rest element must come last
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			pattern.Rest = p.parseRestName()
+			if pattern.Rest == nil {
+				return nil
+			}
+			break
+		}
+
+		el := p.parsePattern()
+		if el == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, el)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return pattern
+}
+
+// Parses a hash pattern (e.g. {"type": "x", ...rest}); a rest element must come last
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			pattern.Rest = p.parseRestName()
+			if pattern.Rest == nil {
+				return nil
+			}
+			break
+		}
+
+		pair := p.parseHashPatternPair()
+		if pair == nil {
+			return nil
+		}
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return pattern
+}
+
+/*
+Parses one entry of a hash pattern. Keys are strings or integers ("type": t), or
+bare names which stand for the string of the same name (age: years). A bare name
+without a pattern binds the value to that name ({name} is short for {name: name}).
+*/
+func (p *Parser) parseHashPatternPair() *ast.HashPatternPair {
+	pair := &ast.HashPatternPair{}
+
+	switch p.curToken.Type {
+	case token.IDENT:
+		key := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		pair.Key = key
+		if !p.peekTokenIs(token.COLON) { // Shorthand
+			pair.Value = &ast.BindingPattern{Token: p.curToken, Name: key}
+			return pair
+		}
+	case token.STRING, token.INT:
+		pair.Key = p.prefixParseFns[p.curToken.Type]()
+	default:
+		msg := fmt.Sprintf("expected a name, string or integer key in hash pattern at %d:%d, got %s instead", p.curToken.Line, p.curToken.Column, p.curToken.Type)
+		p.errors = append(p.errors, errors.New(msg))
+		return nil
+	}
+
+	if pair.Key == nil || !p.expectPeek(token.COLON) {
+		return nil
+	}
+
+	p.nextToken()
+	pair.Value = p.parsePattern()
+	if pair.Value == nil {
+		return nil
+	}
+	return pair
+}
+
+// Parses the name after ... in an array or hash pattern
+func (p *Parser) parseRestName() *ast.Identifier {
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+It parses the rest of a slice once its start (nil if it was left out) and the first colon have been read.
+The end and the step are both optional, and so is the second colon: arr[1:], arr[1::2] and arr[1:10:] are all valid.
+*/
+func (p *Parser) parseSlice(bracket token.Token, left, start ast.Expression) ast.Expression {
+	exp := &ast.SliceExpression{Token: bracket, Left: left, Start: start}
+
+	if !p.peekTokenIs(token.COLON) && !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.End = p.parseExpression(LOWEST)
+		if exp.End == nil { // Written but invalid, which is not the same as left out
+			return nil
+		}
+	}
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		if !p.peekTokenIs(token.RBRACKET) {
+			p.nextToken()
+			exp.Step = p.parseExpression(LOWEST)
+			if exp.Step == nil {
+				return nil
+			}
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return exp
+}
+
+// Parses a range expression (e.g. 0..n) once the start and the .. have been read
+func (p *Parser) parseRangeExpression(left ast.Expression) ast.Expression {
+	exp := &ast.RangeExpression{Token: p.curToken, Start: left}
+
+	p.nextToken()
+	exp.End = p.parseExpression(RANGE)
+	if exp.End == nil {
+		return nil
+	}
+	return exp
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+// Parses a struct declaration (e.g. struct Point { x, y }); field names must be unique
+func (p *Parser) parseStructDecl() *ast.StructDecl {
+	decl := &ast.StructDecl{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	decl.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		if seen[p.curToken.Literal] {
+			p.errorAtCurrent(fmt.Sprintf("duplicate field %s in struct %s", p.curToken.Literal, decl.Name.Value))
+			return nil
+		}
+		seen[p.curToken.Literal] = true
+		decl.Fields = append(decl.Fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return decl
+}
+
+/*
+It parses a struct literal once the type name and the { have been read (e.g. Point{x: 1, y: 2}).
+Only a plain name can be followed by a struct literal's braces.
+*/
+func (p *Parser) parseStructLiteral(left ast.Expression) ast.Expression {
+	typeName, ok := left.(*ast.Identifier)
+	if !ok {
+		if left == nil {
+			p.errorAtCurrent("unexpected {")
+		} else {
+			p.errorAtCurrent(fmt.Sprintf("unexpected { after %s", left.String()))
+		}
+		return nil
+	}
+
+	lit := &ast.StructLiteral{Token: p.curToken, Type: typeName}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		field := &ast.StructField{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.nextToken()
+		field.Value = p.parseExpression(LOWEST)
+		if field.Value == nil { // parseExpression has already reported why
+			return nil
+		}
+		lit.Fields = append(lit.Fields, field)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return lit
+}
+
+// Parses member access by name once the object and the . have been read (e.g. p.x)
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: object}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
+/*
+It parses a field update once the target and the = have been read (e.g. p.x = 3).
+Assignment is right-associative, so a.x = b.y = 1 sets both fields.
+*/
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	member, ok := target.(*ast.MemberExpression)
+	if target == nil {
+		p.errorAtCurrent("nothing to assign to")
+		return nil
+	}
+	if !ok || member.Optional {
+		msg := fmt.Sprintf("cannot assign to %s at %d:%d", target.String(), p.curToken.Line, p.curToken.Column)
+		p.errors = append(p.errors, errors.New(msg))
+		return nil
+	}
+
+	exp := &ast.AssignExpression{Token: p.curToken, Target: member}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(ASSIGN - 1)
+	if exp.Value == nil { // parseExpression has already reported why
+		return nil
+	}
+
+	return exp
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+It parses the type annotation starting at the current token:
+
+	int
+	Point
+	fn(int, string) -> bool
+
+@return ast.TypeAnnotation - The annotation, or nil if it is invalid
+*/
+func (p *Parser) parseTypeAnnotation() ast.TypeAnnotation {
+	switch p.curToken.Type {
+	case token.IDENT, token.NULL:
+		return &ast.NamedType{Token: p.curToken, Name: p.curToken.Literal}
+	case token.FUNCTION:
+		return p.parseFunctionType()
+	default:
+		p.errorAtCurrent(fmt.Sprintf("expected a type, got %s instead", p.curToken.Type))
+		return nil
+	}
+}
+
+// Parses a function type (e.g. fn(int, string) -> bool) starting at fn
+func (p *Parser) parseFunctionType() ast.TypeAnnotation {
+	ft := &ast.FunctionType{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	for !p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		param := p.parseTypeAnnotation()
+		if param == nil {
+			return nil
+		}
+		ft.Parameters = append(ft.Parameters, param)
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RPAREN) || !p.expectPeek(token.ARROW) {
+		return nil
+	}
+
+	p.nextToken()
+	ft.Return = p.parseTypeAnnotation()
+	if ft.Return == nil {
+		return nil
+	}
+	return ft
+}
+
+/*
+It parses an annotation introduced by the given token (: after a name, -> after a
+parameter list) if the next token is that token.
+
+@param sep token.TokenType - The token that introduces the annotation
+
+@return ast.TypeAnnotation - The annotation, nil if there is none
+
+@return bool - False if an annotation was started but is invalid
+*/
+func (p *Parser) parseOptionalType(sep token.TokenType) (ast.TypeAnnotation, bool) {
+	if !p.peekTokenIs(sep) {
+		return nil, true
+	}
+	p.nextToken()
+	p.nextToken()
+	t := p.parseTypeAnnotation()
+	return t, t != nil
+}
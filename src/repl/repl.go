@@ -17,8 +17,10 @@ Start starts the REPL (Read-Eval-Print-Loop)
 @param in io.Reader - The input to read from (usually os.Stdin) (type: io.Reader) (required)
 
 @param out io.Writer - The output to write to (usually os.Stdout) (type: io.Writer) (required)
+
+@param opts ...lexer.Option - Options for lexing each line (e.g. lexer.WithDialect(token.Bro)) (optional)
 */
-func Start(in io.Reader, out io.Writer) {
+func Start(in io.Reader, out io.Writer, opts ...lexer.Option) {
 	scanner := bufio.NewScanner(in)
 
 	for {
@@ -36,7 +38,7 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		l := lexer.New(line)
+		l := lexer.New(line, opts...)
 
 		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() { // Iterate through the tokens until EOF is reached
 			fmt.Printf("%+v\n", tok)
@@ -0,0 +1,97 @@
+package token
+
+import "sort"
+
+/*
+Dialect is a keyword table: the word a source file uses for each keyword. Words that are
+keywords in one dialect are plain identifiers in another, so the same program can be
+written with classic keywords (let, fn, return) or with bro ones (yo, bro, bruh).
+*/
+type Dialect struct {
+	Name      string               // The name used in pragmas and on the command line (e.g. bro)
+	keywords  map[string]TokenType // Keyword token types, by the word that spells them
+	spellings map[TokenType]string // The word that spells each keyword token type
+}
+
+/*
+NewDialect creates a dialect from a keyword table. Every keyword token type must be
+spelled by exactly one word, which is what makes translating between dialects lossless.
+
+@param name string - The name of the dialect
+
+@param keywords map[string]TokenType - Keyword token types, by the word that spells them
+
+@return *Dialect - The new dialect
+*/
+func NewDialect(name string, keywords map[string]TokenType) *Dialect {
+	d := &Dialect{Name: name, keywords: keywords, spellings: map[TokenType]string{}}
+	for word, t := range keywords {
+		d.spellings[t] = word
+	}
+	return d
+}
+
+// LookupIdent returns the keyword token type a word spells in this dialect, token.IDENT if it is not a keyword
+func (d *Dialect) LookupIdent(ident string) TokenType {
+	if tok, ok := d.keywords[ident]; ok {
+		return tok
+	}
+	return IDENT
+}
+
+// Spelling returns the word this dialect uses for a keyword token type, false if the type is not a keyword
+func (d *Dialect) Spelling(t TokenType) (string, bool) {
+	word, ok := d.spellings[t]
+	return word, ok
+}
+
+// Classic is the default dialect, with the keywords BroLang has always had
+var Classic = NewDialect("classic", keywords)
+
+// Bro is the slang dialect. Keywords it does not rename are spelled as in Classic.
+var Bro = NewDialect("bro", withKeywords(keywords, map[TokenType]string{
+	LET:      "yo",
+	FUNCTION: "bro",
+	RETURN:   "bruh",
+	TRUE:     "facts",
+	FALSE:    "nah",
+	IF:       "ayo",
+	ELSE:     "nvm",
+	NULL:     "ghosted",
+	MATCH:    "vibe",
+	THROW:    "yeet",
+}))
+
+// dialects are the dialects that can be selected by name
+var dialects = map[string]*Dialect{
+	Classic.Name: Classic,
+	Bro.Name:     Bro,
+}
+
+// LookupDialect returns the dialect with the given name, false if there is none
+func LookupDialect(name string) (*Dialect, bool) {
+	d, ok := dialects[name]
+	return d, ok
+}
+
+// DialectNames returns the names of the dialects, sorted
+func DialectNames() []string {
+	names := []string{}
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withKeywords copies a keyword table, respelling the given token types
+func withKeywords(base map[string]TokenType, respell map[TokenType]string) map[string]TokenType {
+	table := map[string]TokenType{}
+	for word, t := range base {
+		if spelling, ok := respell[t]; ok {
+			word = spelling
+		}
+		table[word] = t
+	}
+	return table
+}
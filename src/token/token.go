@@ -6,6 +6,9 @@ type TokenType string // Type of token (e.g. IDENT, INT, ASSIGN, etc.)
 type Token struct {
 	Type    TokenType // Type of token (e.g. IDENT, INT, ASSIGN, etc.)
 	Literal string    // Literal value of token (e.g. add, foobar, 1234567890, etc.)
+	Line    int       // Line the token starts on (starting at 1)
+	Column  int       // Column the token starts at, counted in characters (starting at 1)
+	Offset  int       // Byte offset of the token's first char in the input (starting at 0)
 }
 
 // Token types
@@ -13,9 +16,18 @@ const (
 	ILLEGAL = "ILLEGAL" // Unknown tokens
 	EOF     = "EOF"     // End of File
 
+	DOC_COMMENT = "DOC_COMMENT" // /// documentation for the following declaration
+
 	// Identifiers + literals
-	IDENT = "IDENT" // add, foobar, x, y, ...
-	INT   = "INT"   // 1234567890
+	IDENT  = "IDENT"  // add, foobar, x, y, ...
+	INT    = "INT"    // 1234567890
+	STRING = "STRING" // "foobar"
+
+	// Interpolated strings are split around their ${...} parts: "a ${x} b ${y} c"
+	// lexes as INTERP_HEAD("a "), x, INTERP_MIDDLE(" b "), y, INTERP_TAIL(" c")
+	INTERP_HEAD   = "INTERP_HEAD"   // From the opening quote up to the first ${
+	INTERP_MIDDLE = "INTERP_MIDDLE" // From a closing } up to the next ${
+	INTERP_TAIL   = "INTERP_TAIL"   // From the last closing } up to the closing quote
 
 	// Operators
 	ASSIGN   = "="
@@ -24,21 +36,52 @@ const (
 	BANG     = "!"
 	SLASH    = "/"
 	ASTERISK = "*"
+	PERCENT  = "%"
+	POWER    = "**"
+
+	// Logical operators
+	AND = "&&"
+	OR  = "||"
+
+	// Null-safe operators
+	OPTIONAL_CHAIN = "?." // a?.b and a?.[i]
+	NULLISH        = "??" // a ?? b
+
+	// Pipeline operator
+	PIPE = "|>" // data |> f(x)
+
+	// Bitwise operators
+	BIT_AND     = "&"
+	BIT_OR      = "|"
+	BIT_XOR     = "^"
+	BIT_NOT     = "~"
+	SHIFT_LEFT  = "<<"
+	SHIFT_RIGHT = ">>"
 
 	// Comparators
 	LT     = "<"
 	GT     = ">"
+	LT_EQ  = "<="
+	GT_EQ  = ">="
 	EQ     = "=="
 	NOT_EQ = "!="
 
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
+	DOT       = "."
+	ELLIPSIS  = "..."
+	DOT_DOT   = ".." // 0..10
+	FAT_ARROW = "=>"
+	ARROW     = "->"
 
-	LPAREN = "("
-	RPAREN = ")"
-	LBRACE = "{"
-	RBRACE = "}"
+	LPAREN   = "("
+	RPAREN   = ")"
+	LBRACE   = "{"
+	RBRACE   = "}"
+	LBRACKET = "["
+	RBRACKET = "]"
 
 	// Keywords
 	FUNCTION = "FUNCTION"
@@ -48,17 +91,53 @@ const (
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
 	RETURN   = "RETURN"
+	NULL     = "NULL"
+	MATCH    = "MATCH"
+	STRUCT   = "STRUCT"
+	IMPL     = "IMPL"
+	TRAIT    = "TRAIT"
+	FOR      = "FOR"
+	ENUM     = "ENUM"
+	THROW    = "THROW"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	AS       = "AS"
+	MACRO    = "MACRO"
+	YIELD    = "YIELD"
+	SPAWN    = "SPAWN"
+	SELECT   = "SELECT"
 )
 
 // Keywords map
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"if":     IF,
-	"else":   ELSE,
-	"true":   TRUE,
-	"false":  FALSE,
-	"return": RETURN,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"if":      IF,
+	"else":    ELSE,
+	"true":    TRUE,
+	"false":   FALSE,
+	"return":  RETURN,
+	"null":    NULL,
+	"match":   MATCH,
+	"struct":  STRUCT,
+	"impl":    IMPL,
+	"trait":   TRAIT,
+	"for":     FOR,
+	"enum":    ENUM,
+	"throw":   THROW,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"import":  IMPORT,
+	"export":  EXPORT,
+	"as":      AS,
+	"macro":   MACRO,
+	"yield":   YIELD,
+	"spawn":   SPAWN,
+	"select":  SELECT,
 }
 
 /*
@@ -69,14 +148,12 @@ We use this function when we encounter an identifier in the input.
 
 For example, if we encounter the identifier let, we check if it is a keyword.
 If it is, we return the token.LET constant. If it is not, we return the token.IDENT constant.
+It uses the classic keywords; a lexer for another dialect uses Dialect.LookupIdent instead.
 
 @params ident string
 
 @return TokenType constant or token.IDENT constant (string)
 */
 func LookupIdent(ident string) TokenType {
-	if tok, ok := keywords[ident]; ok {
-		return tok
-	}
-	return IDENT
+	return Classic.LookupIdent(ident)
 }
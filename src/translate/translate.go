@@ -0,0 +1,76 @@
+package translate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kriptonian1/BroLang/src/lexer"
+	"github.com/kriptonian1/BroLang/src/token"
+)
+
+/*
+Translate rewrites a source file from one dialect into another. Only the keywords change:
+comments, whitespace, strings and identifiers are copied as they are, so translating
+back gives the original file.
+
+The dialect pragma is rewritten to name the new dialect, classic included, so a file that
+starts with one translates back to itself. A file without one gets one, unless it is
+translated to the classic dialect, which needs none.
+
+@param source string - The source file
+
+@param from *token.Dialect - The dialect of the source, used when it has no pragma of its own
+
+@param to *token.Dialect - The dialect to translate into
+
+@return string - The translated source
+
+@return error - If the source has a bad pragma, or an identifier that is a keyword in the new dialect
+*/
+func Translate(source string, from, to *token.Dialect) (string, error) {
+	l := lexer.New(source, lexer.WithDialect(from))
+	if err := l.Err(); err != nil {
+		return "", err
+	}
+	from = l.Dialect()
+
+	var out strings.Builder
+	copied := 0 // The source up to here is already in out
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if tok.Type == token.IDENT {
+			if to.LookupIdent(tok.Literal) != token.IDENT {
+				return "", fmt.Errorf("%s at %d:%d is a keyword in the %s dialect; rename it before translating", tok.Literal, tok.Line, tok.Column, to.Name)
+			}
+			continue
+		}
+
+		spelling, ok := from.Spelling(tok.Type)
+		if !ok || spelling != tok.Literal { // Not a keyword
+			continue
+		}
+		translated, _ := to.Spelling(tok.Type)
+		out.WriteString(source[copied:tok.Offset])
+		out.WriteString(translated)
+		copied = tok.Offset + len(tok.Literal)
+	}
+	out.WriteString(source[copied:])
+
+	return withPragma(out.String(), to), nil
+}
+
+// withPragma sets the dialect pragma of a translated source to name the dialect it is now in
+func withPragma(source string, to *token.Dialect) string {
+	_, hasPragma := lexer.Pragma(source)
+	if !hasPragma && to == token.Classic {
+		return source
+	}
+
+	body := source
+	if hasPragma {
+		body = ""
+		if i := strings.IndexByte(source, '\n'); i >= 0 {
+			body = source[i+1:]
+		}
+	}
+	return "// dialect: " + to.Name + "\n" + body
+}
@@ -38,3 +38,54 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", programReturn.String())
 	}
 }
+
+func TestModify(t *testing.T) {
+	one := func() ast.Expression {
+		return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "1"}, Value: 1}
+	}
+	two := func() ast.Expression {
+		return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "2"}, Value: 2}
+	}
+
+	turnOneIntoTwo := func(node ast.Node) ast.Node {
+		if integer, ok := node.(*ast.IntegerLiteral); ok && integer.Value == 1 {
+			return two()
+		}
+		return node
+	}
+
+	tests := []struct {
+		input    ast.Node // The tree to modify
+		expected string   // The modified tree as printed
+	}{
+		{one(), "2"},
+		{&ast.Program{Statements: []ast.Statement{&ast.ExpressionStatement{Expression: one()}}}, "2"},
+		{&ast.InfixExpression{Left: one(), Operator: "+", Right: two()}, "(2 + 2)"},
+		{&ast.IndexExpression{Left: one(), Index: one()}, "(2[2])"},
+		{&ast.ReturnStatement{Token: token.Token{Literal: "return"}, ReturnValue: one()}, "return 2;"},
+		{
+			&ast.CallExpression{
+				Function:  &ast.Identifier{Value: "f"},
+				Arguments: []ast.Expression{one(), &ast.NamedArgument{Name: &ast.Identifier{Value: "y"}, Value: one()}},
+			},
+			"f(2, y: 2)",
+		},
+		{
+			&ast.MatchExpression{
+				Subject: one(),
+				Arms: []*ast.MatchArm{{
+					Pattern: &ast.LiteralPattern{Value: one()},
+					Body:    one(),
+				}},
+			},
+			"match (2) { 2 => 2 }",
+		},
+	}
+
+	for _, tt := range tests {
+		modified := ast.Modify(tt.input, turnOneIntoTwo)
+		if modified.String() != tt.expected {
+			t.Errorf("modified wrong. expected=%q, got=%q", tt.expected, modified.String())
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/kriptonian1/BroLang/src/checker"
+	"github.com/kriptonian1/BroLang/src/lexer"
+	"github.com/kriptonian1/BroLang/src/parser"
+)
+
+// checkSource parses input and type checks it, failing the test on parser errors
+func checkSource(t *testing.T, input string) []error {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	return checker.Check(program)
+}
+
+func TestCheckAcceptsWellTypedPrograms(t *testing.T) {
+	tests := []string{
+		"let x: int = 5; let y: int = x * 2 + 1;",
+		`let greet = fn(name: string) -> string { "Hello " + name; }; let s: string = greet("bro");`,
+		"let fact = fn(n: int) -> int { match (n) { 0 => 1, _ => n * fact(n - 1) }; };",
+		"let f = fn(x) { x + 1; }; f(\"unannotated code is not checked\");",
+		"let id = fn(x) { x; }; let n: int = id(1);",
+		"let apply: fn(fn(int) -> int, int) -> int = fn(f: fn(int) -> int, x: int) -> int { f(x); };",
+		"struct Point { x, y } trait Shape { fn area(self) -> int } impl Shape for Point { fn area(self) -> int { self.x * self.y; } } let s: Shape = Point{x: 1, y: 2};",
+		"enum Result { Ok(v), Err(e) } let r: Result = Ok(1); let p: Result = Err(\"no\");",
+		"let b: bool = 1 < 2 && \"a\" != \"b\";",
+		"let port = fn(a) { a || 8080; }; let n = 1 && 2; let m: int = 1 || 2;",
+		"let not: bool = !5; let both: bool = !(1 < 2) || !\"s\";",
+		"let early = fn(n: int) -> int { return n; };",
+		"let nothing = fn() -> null { return; };",
+		"let empty = fn() -> null { }; let unchecked = fn() { let x = 1; };",
+		"let v: int = null ?? 5;",
+		"let point: Point = Point{x: 1, y: 2}; struct Point { x, y }",
+		`let s: string = "hello"; let t: string = s[1:-1:2]; let r: range = 0..10;`,
+		"let f = fn(a) { a[::-1]; };",
+		"let double = fn(x: int) -> int { x * 2; }; let n: int = 5 |> double |> double;",
+		"let add: fn(int, int) -> int = (a, b) => a + b;",
+		"let f = fn(x, y = 10, ...rest) { x; }; f(1); f(1, 2, 3, 4); f(y: 2, x: 1); f(...[1]);",
+		"let f = fn(x) { x; }; let g = fn(f) { f(1, 2); }; let h = fn() { let f = 5; f; };",
+		"let add = fn(a: int, b: int) -> int { a + b; }; let xs: array = [1, 2]; let n: int = add(...xs); let all: array = [0, ...xs][1:];",
+		`let add = fn(a: int, b: int) -> int { a + b; }; add(1, ...[2], "after a spread, positions are unknown");`,
+		`let defaults: hash = {"port": 80}; let port = 8080; let config: hash = {...defaults, port};`,
+	}
+
+	for _, input := range tests {
+		if errs := checkSource(t, input); len(errs) > 0 {
+			t.Errorf("%s: unexpected type errors %v", input, errs)
+		}
+	}
+}
+
+func TestCheckReportsMismatches(t *testing.T) {
+	tests := []struct {
+		input    string // The program
+		expected string // The only error it should produce
+	}{
+		{`let x: int = "five";`, "cannot use string as int in let x at 1:14"},
+		{`let add = fn(a: int, b: int) -> int { a + b; }; add(1, "2");`, "cannot use string as int in argument 2 of add at 1:56"},
+		{"let f = fn(n: int) -> string { n; };", "cannot return int from a function returning string at 1:32"},
+		{"let f = fn(n: int) -> string { return n; };", "cannot return int from a function returning string at 1:32"},
+		{"let f = fn() -> int {};", "cannot return null from a function returning int at 1:21"},
+		{"let f = fn(n: int) -> int { let m = n; };", "cannot return null from a function returning int at 1:27"},
+		{"let f = fn(n: int = true) { n; };", "cannot use bool as int for parameter n at 1:21"},
+		{"let x: Nope = 1;", "unknown type Nope at 1:8"},
+		{"1 + true;", "operator + cannot be applied to int and bool at 1:3"},
+		{`"a" * 2;`, "operator * cannot be applied to string and int at 1:5"},
+		{"let x: int = 1; x(2);", "cannot call x of type int at 1:17"},
+		{"let x: int = true && false;", "cannot use bool as int in let x at 1:14"},
+		{`let f = fn(x: int, y: string = "") { x; }; f(1, y: 2);`, "cannot use int as string in argument y of f at 1:52"},
+		{"let x: string = fn(a: int) -> int { a; };", "cannot use fn(int) -> int as string in let x at 1:17"},
+		{"let f: fn(string) -> int = fn(a: int) -> int { a; };", "cannot use fn(int) -> int as fn(string) -> int in let f at 1:28"},
+		{"struct Point { x, y } trait Shape { fn area(self) } let s: Shape = Point{x: 1, y: 2};", "cannot use Point as Shape in let s at 1:68"},
+		{"match (x) { n if n + 1 => n };", "match guard must be bool, got int at 1:18"},
+		{"let p = P{x: 1, z: 2}; struct P { x, y }", "unknown field z in P at 1:17"},
+		{"let n = ~\"s\";", "operator ~ cannot be applied to string at 1:9"},
+		{`let s: string = "ab"; s[1:"x"];`, "cannot use string as int in a slice of s at 1:27"},
+		{"let b: bool = true; b[1:];", "cannot slice b of type bool at 1:21"},
+		{"let add = fn(a: int, b: int) -> int { a + b; }; add(...5);", "cannot spread int into the arguments of add; expected array at 1:53"},
+		{"let f = fn(x, y = 10) { x; }; f(1, 2, 3);", "f expects 1-2 arguments, got 3 at 1:31"},
+		{"let f = fn(x: int) { x; }; f();", "f expects 1 argument, got 0 at 1:28"},
+		{"let f = fn(x, y = 1) { x; }; f(1, z: 2);", "f has no parameter named z at 1:30"},
+		{"let g = (a, b) => a; g(1);", "g expects 2 arguments, got 1 at 1:22"},
+		{"fn(x) { x; }(1, 2);", "function expects 1 argument, got 2 at 1:1"},
+		{`let h = {"k": 1}; [0, ...h];`, "cannot spread hash into an array; expected array at 1:23"},
+		{"let xs = [1]; let h = {...xs};", "cannot spread array into a hash; expected hash at 1:24"},
+		{`let inc = fn(x: int) -> int { x + 1; }; "s" |> inc;`, "cannot use string as int in argument 1 of inc at 1:41"},
+		{`let r = 1.."a";`, "cannot use string as int in a range at 1:12"},
+	}
+
+	for _, tt := range tests {
+		errs := checkSource(t, tt.input)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected 1 error, got %v", tt.input, errs)
+			continue
+		}
+		if errs[0].Error() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, errs[0].Error())
+		}
+	}
+}
+
+func TestInferBindings(t *testing.T) {
+	input := `let id = fn(x) { x; };
+let a = id(1);
+let b = id("s");
+let compose = fn(f, g) { fn(x) { f(g(x)); }; };
+let fact = fn(n) { match (n) { 0 => 1, _ => n * fact(n - 1) }; };
+let k = fn(x, y) { x; };
+let early = fn(n) { return n + 1; };
+let nothing = fn() { };
+let add = fn(a: int, b) { a + b; };
+let twice = fn(f: fn(any) -> any, x) { f(f(x)); };
+enum Result { Ok(v), Err(e) }
+let ok = Ok(1);
+struct Point { x, y }
+impl Point { fn scale(self, n) { Point{x: self.x * n, y: self.y * n}; } }
+let rest = fn(x, ...xs) { xs; };
+let both = 1 && 2;
+let sum = fn(x: int, y = 1) { x + y; };
+let three = sum(1, y: 2);
+`
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	inference, errs := checker.Infer(program)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected inference errors %v", errs)
+	}
+
+	expected := []string{
+		"id: fn('a) -> 'a",
+		"a: int",
+		"b: string",
+		"compose: fn(fn('a) -> 'b, fn('c) -> 'a) -> fn('c) -> 'b",
+		"fact: fn(int) -> int",
+		"k: fn('a, 'b) -> 'a",
+		"early: fn(int) -> int",
+		"nothing: fn() -> null",
+		"add: fn(int, int) -> int",
+		"twice: fn(fn('a) -> 'a, 'a) -> 'a",
+		"ok: Result",
+		"Point.scale: fn(Point, int) -> Point",
+		"rest: fn('a, ...array) -> array",
+		"both: int",
+		"sum: fn(int, int) -> int",
+		"three: int",
+	}
+	if len(inference.Bindings) != len(expected) {
+		t.Fatalf("expected %d bindings, got %d: %v", len(expected), len(inference.Bindings), inference.Bindings)
+	}
+	for i, b := range inference.Bindings {
+		if got := b.Name + ": " + b.Type; got != expected[i] {
+			t.Errorf("bindings[%d] wrong. expected=%q, got=%q", i, expected[i], got)
+		}
+	}
+
+	queries := []struct {
+		line, column int    // The position asked about
+		expected     string // The name there and its type
+	}{
+		{1, 5, "id: fn('a) -> 'a"},
+		{1, 13, "x: 'a"},
+		{2, 10, "id: fn(int) -> int"}, // A use of a polymorphic function shows the type it is used at
+		{3, 9, "id: fn(string) -> string"},
+		{5, 15, "n: int"},
+	}
+	for _, q := range queries {
+		got, ok := inference.TypeAt(q.line, q.column)
+		if !ok || got != q.expected {
+			t.Errorf("TypeAt(%d, %d) wrong. expected=%q, got=%q", q.line, q.column, q.expected, got)
+		}
+	}
+	if _, ok := inference.TypeAt(1, 1); ok {
+		t.Errorf("TypeAt(1, 1) found a name on the let keyword")
+	}
+}
+
+func TestInferReportsFailures(t *testing.T) {
+	tests := []struct {
+		input    string // The program
+		expected string // The only error it should produce
+	}{
+		{`let c = 1 + "s";`, "operator + cannot be applied to int and string at 1:11"},
+		{"let add = fn(a, b) { a - b; }; add(1, true);", "expected int, got bool in argument 2 of add at 1:39"},
+		{"let bad = fn(x) { x(x); };", "infinite type: 'a = fn('a) -> 'b in the call of x at 1:19"},
+		{`let f = fn(x) { match (x) { 0 => "zero", _ => x } };`, "expected string, got int in the match arm at 1:47"},
+		{`let f = fn(n: int) -> string { n; };`, "expected string, got int in the return value at 1:32"},
+		{`let x: string = 5;`, "expected string, got int in let x at 1:17"},
+		{"let x = 1; x(2);", "cannot call x of type int at 1:12"},
+		{"let f = fn() -> int {};", "expected int, got null in the return value at 1:21"},
+		{"let f = fn(n) { let b: bool = n; n + 1; };", "operator + cannot be applied to bool and int at 1:36"},
+		{"let f = fn(x) { return 1; return \"s\"; };", "expected int, got string in the return value at 1:27"},
+		{"let f = fn(s) { s[1:true]; };", "expected int, got bool in a slice of s at 1:21"},
+		{"let n = 5; let m = n[1:];", "cannot slice n of type int at 1:20"},
+		{"let f = fn(xs) { [...xs, ...{}]; };", "expected array, got hash in the spread into an array at 1:26"},
+		{`let twice = (f, x) => f(f(x)); let n = "s" |> twice(x => x * 2);`, "expected fn('a) -> 'a, got string in argument 1 of twice at 1:40"},
+		{`let f = fn(x: int, y = 1) { x + y; }; f(1, y: "s");`, "expected int, got string in argument y of f at 1:47"},
+		{"let f = fn(x, y = 1) { x; }; f(1, 2, 3);", "f expects 1-2 arguments, got 3 at 1:30"},
+		{"let f = fn(g: fn(int) -> int) { g(1, 2); };", "too many arguments to g: expected at most 1, got 2 at 1:33"},
+		{"let f = fn(...xs) { xs + 1; };", "operator + cannot be applied to array and int at 1:24"},
+		{"struct P { x, y } let p = P{x: 1, z: 2};", "unknown field z in P at 1:35"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		_, errs := checker.Infer(program)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected 1 error, got %v", tt.input, errs)
+			continue
+		}
+		if errs[0].Error() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, errs[0].Error())
+		}
+	}
+}
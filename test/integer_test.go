@@ -0,0 +1,112 @@
+package test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/kriptonian1/BroLang/src/integer"
+)
+
+// parseInt parses a decimal number with an optional leading minus, failing the test if it is not one
+func parseInt(t *testing.T, s string) integer.Int {
+	n, ok := integer.Parse(strings.TrimPrefix(s, "-"))
+	if !ok {
+		t.Fatalf("could not parse %q", s)
+	}
+	if strings.HasPrefix(s, "-") {
+		return n.Neg()
+	}
+	return n
+}
+
+func TestIntegerArithmetic(t *testing.T) {
+	max, min := integer.New(math.MaxInt64), integer.New(math.MinInt64)
+	one := integer.New(1)
+
+	tests := []struct {
+		got      integer.Int // The result of the operation
+		expected string      // The result in decimal
+		big      bool        // Whether the result needs the big form
+	}{
+		{integer.New(2).Add(integer.New(3)), "5", false},
+		{max.Add(one), "9223372036854775808", true},
+		{min.Sub(one), "-9223372036854775809", true},
+		{min.Neg(), "9223372036854775808", true},
+		{max.Mul(integer.New(2)), "18446744073709551614", true},
+		{min.Mul(integer.New(-1)), "9223372036854775808", true},
+		{integer.New(-4).Mul(integer.New(5)), "-20", false},
+		{max.Add(one).Sub(one), "9223372036854775807", false}, // Demoted again
+		{max.Add(one).Neg().Add(integer.New(0)), "-9223372036854775808", false},
+		{parseInt(t, "100000000000000000000").Sub(parseInt(t, "99999999999999999999")), "1", false},
+	}
+
+	for i, tt := range tests {
+		if tt.got.String() != tt.expected {
+			t.Errorf("tests[%d]: expected=%s, got=%s", i, tt.expected, tt.got)
+		}
+		if tt.got.IsBig() != tt.big {
+			t.Errorf("tests[%d]: %s big=%t, expected %t", i, tt.got, tt.got.IsBig(), tt.big)
+		}
+	}
+}
+
+func TestIntegerDivisionAndPower(t *testing.T) {
+	tests := []struct {
+		op       func(x, y integer.Int) (integer.Int, error) // The operation
+		x, y     string                                      // The operands
+		expected string                                      // The result, or the error
+	}{
+		{integer.Int.Quo, "-7", "2", "-3"},
+		{integer.Int.Rem, "-7", "2", "-1"},
+		{integer.Int.Quo, "-9223372036854775808", "-1", "9223372036854775808"},
+		{integer.Int.Rem, "-9223372036854775808", "-1", "0"},
+		{integer.Int.Quo, "18446744073709551616", "4294967296", "4294967296"},
+		{integer.Int.Quo, "1", "0", "division by zero"},
+		{integer.Int.Rem, "18446744073709551616", "0", "division by zero"},
+		{integer.Int.Pow, "2", "64", "18446744073709551616"},
+		{integer.Int.Pow, "-3", "3", "-27"},
+		{integer.Int.Pow, "2", "-1", "negative exponent"},
+		{integer.Int.Pow, "2", "99999999999", "result too large"},
+		{integer.Int.Pow, "-5", "100000000000000000000", "result too large"},
+		{integer.Int.Pow, "1", "100000000000000000000", "1"},
+		{integer.Int.Pow, "-1", "99999999999", "-1"},
+		{integer.Int.Pow, "-1", "100000000000000000000", "1"},
+		{integer.Int.Pow, "0", "99999999999", "0"},
+		{integer.Int.Pow, "7", "0", "1"},
+		{integer.Int.Pow, "2", "1048576", "result too large"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.op(parseInt(t, tt.x), parseInt(t, tt.y))
+		result := got.String()
+		if err != nil {
+			result = err.Error()
+		}
+		if result != tt.expected {
+			t.Errorf("%s, %s: expected=%s, got=%s", tt.x, tt.y, tt.expected, result)
+		}
+	}
+}
+
+func TestIntegerComparisonAndHashing(t *testing.T) {
+	big := integer.New(math.MaxInt64).Add(integer.New(1))
+	small := integer.New(math.MaxInt64)
+
+	if big.Cmp(small) != 1 || small.Cmp(big) != -1 || small.Cmp(integer.New(math.MaxInt64)) != 0 {
+		t.Errorf("wrong comparison of %s and %s", big, small)
+	}
+
+	same := parseInt(t, "9223372036854775808")
+	if big.Cmp(same) != 0 || big.HashKey() != same.HashKey() {
+		t.Errorf("big values built differently compare or hash differently")
+	}
+
+	demoted := big.Sub(integer.New(1))
+	if demoted != small || demoted.HashKey() != small.HashKey() {
+		t.Errorf("a demoted value is not the same as the small one")
+	}
+	if integer.New(5).HashKey() == integer.New(6).HashKey() {
+		t.Errorf("different values hash the same")
+	}
+}
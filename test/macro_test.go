@@ -0,0 +1,141 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/kriptonian1/BroLang/src/ast"
+	"github.com/kriptonian1/BroLang/src/lexer"
+	"github.com/kriptonian1/BroLang/src/macro"
+	"github.com/kriptonian1/BroLang/src/parser"
+)
+
+func TestDefineMacros(t *testing.T) {
+	input := `
+let number = 1;
+let function = fn(x, y) { x + y };
+let mymacro = macro(x, y) { x + y; };
+`
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	macros := macro.DefineMacros(program)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("wrong number of statements. got=%d", len(program.Statements))
+	}
+	m, ok := macros["mymacro"]
+	if !ok {
+		t.Fatalf("macro mymacro not defined")
+	}
+	if len(m.Parameters) != 2 || m.Parameters[0].Value != "x" || m.Parameters[1].Value != "y" {
+		t.Errorf("macro parameters wrong. got=%v", m.Parameters)
+	}
+	if m.Body.String() != "{ (x + y) }" {
+		t.Errorf("macro body wrong. got=%q", m.Body.String())
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string // The program with its macros
+		expected string // The expanded program as printed
+	}{
+		{
+			`let infixExpression = macro() { quote(1 + 2); }; infixExpression();`,
+			`(1 + 2)`,
+		},
+		{
+			`let reverse = macro(a, b) { quote(unquote(b) - unquote(a)); }; reverse(2 + 2, 10 - 5);`,
+			`((10 - 5) - (2 + 2))`,
+		},
+		{
+			`let twice = macro(x) { quote(unquote(x) + unquote(x)); }; twice(twice(n));`,
+			`((n + n) + (n + n))`,
+		},
+		{
+			`let unless = macro(cond, body) { quote(match (unquote(cond)) { false => unquote(body), _ => null }); }; unless(10 > 5, log("no"));`,
+			`match ((10 > 5)) { false => log("no"), _ => null }`,
+		},
+		{
+			`let inc = macro(x) { quote(unquote(x) + 1); }; let twiceInc = macro(x) { quote(inc(inc(unquote(x)))); }; twiceInc(n);`,
+			`((n + 1) + 1)`, // Macro calls in the output are expanded too
+		},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		macros := macro.DefineMacros(program)
+		expanded, err := macro.ExpandMacros(program, macros)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.input, err)
+		}
+		if expanded.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, expanded.String())
+		}
+	}
+}
+
+func TestExpandMacrosCopiesArguments(t *testing.T) {
+	p := parser.New(lexer.New(`let twice = macro(x) { quote(unquote(x) * unquote(x)); }; twice(a + 1);`))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	expanded, err := macro.ExpandMacros(program, macro.DefineMacros(program))
+	if err != nil {
+		t.Fatal(err)
+	}
+	infix := expanded.(*ast.Program).Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+	if infix.Left == infix.Right { // Each splice is its own tree, so later passes can change one without the other
+		t.Errorf("both sides of the expansion are the same node")
+	}
+}
+
+func TestInvalidMacros(t *testing.T) {
+	tests := []struct {
+		input    string // The program with its macros
+		expected string // The error
+	}{
+		{
+			`let m = macro(x) { quote(unquote(x)); }; m(1, 2);`,
+			"macro m expects 1 arguments, got 2 at 1:42",
+		},
+		{
+			`let m = macro(x) { let y = x; quote(y); }; m(1);`,
+			"macro m must have a single quote(...) as its body to be expanded without an evaluator at 1:44",
+		},
+		{
+			`let m = macro(x) { quote(unquote(1 + 1)); }; m(1);`,
+			"macro m: unquote((1 + 1)) needs the evaluator, only parameters can be unquoted at 1:46",
+		},
+		{
+			`let m = macro(x) { quote(m(unquote(x))); }; m(1);`,
+			"macro m is still expanding after 100 rounds at 1:26",
+		},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		_, err := macro.ExpandMacros(program, macro.DefineMacros(program))
+		if err == nil {
+			t.Fatalf("%s: expected an error", tt.input)
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, err.Error())
+		}
+	}
+
+	for _, input := range []string{"macro(x = 1) { x; }", "macro(...xs) { xs; }"} {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
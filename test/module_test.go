@@ -0,0 +1,132 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kriptonian1/BroLang/src/module"
+)
+
+// writeFiles creates the given files (path relative to dir: contents) under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadResolvesImportsAndExports(t *testing.T) {
+	dir := t.TempDir()
+	lib := t.TempDir() // Stands in for a BROPATH directory
+	writeFiles(t, dir, map[string]string{
+		"main.bro":      `import "./utils.bro" as utils; import "./lib/math" as math; import "strings" as strings;`,
+		"utils.bro":     `export let greet = fn(name) { name; }; let private = 1; export let [a, ...rest] = list;`,
+		"lib/math.bro":  `import "../utils.bro" as utils; export struct Point { x, y }`,
+		"lib/extra.bro": ``,
+	})
+	writeFiles(t, lib, map[string]string{
+		"strings.bro": `export enum Case { Upper, Lower }`,
+	})
+
+	loader := module.NewLoader([]string{lib})
+	main, err := loader.Load(filepath.Join(dir, "main.bro"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(main.Imports) != 3 {
+		t.Fatalf("main.Imports does not contain 3 modules. got=%d", len(main.Imports))
+	}
+
+	utils := main.Imports["utils"]
+	if strings.Join(utils.Exports, ",") != "greet,a,rest" {
+		t.Errorf("utils.Exports wrong. got=%v", utils.Exports)
+	}
+
+	math := main.Imports["math"]
+	if math.Imports["utils"] != utils { // The same file is loaded only once
+		t.Errorf("utils was loaded twice")
+	}
+	if strings.Join(math.Exports, ",") != "Point" {
+		t.Errorf("math.Exports wrong. got=%v", math.Exports)
+	}
+
+	if strings.Join(main.Imports["strings"].Exports, ",") != "Case" {
+		t.Errorf("strings.Exports wrong. got=%v", main.Imports["strings"].Exports)
+	}
+}
+
+func TestLoadAcceptsImplsOfImportedTraits(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.bro":  `import "./shape" as shape; struct Sq { side } impl Shape for Sq { fn area(self) { self.side * self.side; } }`,
+		"shape.bro": `export trait Shape { fn area(self) }`,
+	})
+
+	if _, err := module.NewLoader(nil).Load(filepath.Join(dir, "main.bro")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadExpandsMacros(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.bro": `let double = macro(x) { quote(unquote(x) * 2); }; let n = double(21);`,
+	})
+
+	main, err := module.NewLoader(nil).Load(filepath.Join(dir, "main.bro"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := main.Program.String(); got != "let n = (21 * 2);" {
+		t.Errorf("macros not expanded. got=%q", got)
+	}
+}
+
+func TestLoadReportsImportCycles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.bro": `import "./b.bro" as b;`,
+		"b.bro": `import "./c.bro" as c;`,
+		"c.bro": `import "./b.bro" as b;`,
+	})
+
+	_, err := module.NewLoader(nil).Load(filepath.Join(dir, "a.bro"))
+	if err == nil {
+		t.Fatalf("expected an import cycle error")
+	}
+	if err.Error() != "import cycle: b.bro -> c.bro -> b.bro" {
+		t.Errorf("wrong error. got=%q", err.Error())
+	}
+}
+
+func TestLoadReportsMissingModules(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.bro": "let x = 1;\nimport \"./nope.bro\" as nope;",
+	})
+
+	_, err := module.NewLoader(nil).Load(filepath.Join(dir, "main.bro"))
+	if err == nil {
+		t.Fatalf("expected a missing module error")
+	}
+	if err.Error() != `main.bro:2:1: cannot find module "./nope.bro"` {
+		t.Errorf("wrong error. got=%q", err.Error())
+	}
+}
+
+func TestSearchPathFromEnv(t *testing.T) {
+	t.Setenv("BROPATH", strings.Join([]string{"/opt/bro", "", "/usr/lib/bro"}, string(os.PathListSeparator)))
+
+	dirs := module.SearchPathFromEnv()
+	if strings.Join(dirs, ",") != "/opt/bro,/usr/lib/bro" {
+		t.Errorf("wrong search path. got=%v", dirs)
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kriptonian1/BroLang/src/ast"
@@ -106,6 +107,1260 @@ func TestIdentifierExpression(t *testing.T) {
 	}
 }
 
+func TestLetStatementDocComments(t *testing.T) {
+	input := `
+/// The answer.
+/// Computed elsewhere.
+let answer = 42;
+
+/// Dangling: attached to nothing because an expression follows.
+answer;
+
+let undocumented = 1;
+`
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 3 {
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d", len(program.Statements))
+	}
+
+	documented, ok := program.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.LetStatement. got=%T", program.Statements[0])
+	}
+	if documented.Doc != "The answer.\nComputed elsewhere." { // Checks that both lines were attached
+		t.Errorf("documented.Doc wrong. got=%q", documented.Doc)
+	}
+
+	undocumented, ok := program.Statements[2].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("program.Statements[2] is not *ast.LetStatement. got=%T", program.Statements[2])
+	}
+	if undocumented.Doc != "" { // Checks that the dangling comment did not leak forward
+		t.Errorf("undocumented.Doc not empty. got=%q", undocumented.Doc)
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	input := `"Hello ${name}, you have ${a + b * c} items";`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	str, ok := stmt.Expression.(*ast.InterpolatedString)
+	if !ok {
+		t.Fatalf("exp not *ast.InterpolatedString. got=%T", stmt.Expression)
+	}
+
+	expectedLiterals := []string{"Hello ", ", you have ", " items"}
+	if len(str.Literals) != len(expectedLiterals) {
+		t.Fatalf("wrong number of literal parts. expected=%d, got=%d", len(expectedLiterals), len(str.Literals))
+	}
+	for i, lit := range expectedLiterals {
+		if str.Literals[i] != lit {
+			t.Errorf("str.Literals[%d] wrong. expected=%q, got=%q", i, lit, str.Literals[i])
+		}
+	}
+
+	expectedExpressions := []string{"name", "(a + (b * c))"}
+	if len(str.Expressions) != len(expectedExpressions) {
+		t.Fatalf("wrong number of expressions. expected=%d, got=%d", len(expectedExpressions), len(str.Expressions))
+	}
+	for i, exp := range expectedExpressions {
+		if str.Expressions[i].String() != exp {
+			t.Errorf("str.Expressions[%d] wrong. expected=%q, got=%q", i, exp, str.Expressions[i].String())
+		}
+	}
+}
+
+func TestEmptyInterpolationIsAnError(t *testing.T) {
+	l := lexer.New(`"nothing ${} here"`)
+	p := parser.New(l)
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected a parser error for an empty ${}")
+	}
+}
+
+func TestStringsPrintBackEscaped(t *testing.T) {
+	tests := []struct {
+		input    string // A string expression
+		expected string // How it is printed, which must parse back to the same string
+	}{
+		{`"a\"b";`, `"a\"b"`},
+		{`"back\\slash";`, `"back\\slash"`},
+		{`"tab\tnew\nline";`, `"tab\tnew\nline"`},
+		{`"cost: \${price} $5";`, `"cost: \${price} $5"`},
+		{`"say \"${name}\"\n";`, `"say \"${name}\"\n"`},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		printed := program.String()
+		if printed != tt.expected {
+			t.Errorf("%s: printed as %s, expected %s", tt.input, printed, tt.expected)
+		}
+
+		again := parser.New(lexer.New(printed))
+		reparsed := again.ParseProgram()
+		checkParserErrors(t, again)
+		if reparsed.String() != printed {
+			t.Errorf("%s: reparsed as %s", printed, reparsed.String())
+		}
+	}
+}
+
+func TestMatchExpressionParsing(t *testing.T) {
+	input := `match (value) {
+	0 => "zero",
+	-1 => "minus one",
+	[a, b] => a + b,
+	[first, ...others] if first > 10 => others,
+	{"type": "x", "size": [w, _], ...rest} => rest,
+	null => false,
+	_ => "other",
+}`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.MatchExpression. got=%T", stmt.Expression)
+	}
+
+	if exp.Subject.String() != "value" {
+		t.Errorf("exp.Subject wrong. got=%q", exp.Subject.String())
+	}
+
+	expectedArms := []string{
+		`0 => "zero"`,
+		`-1 => "minus one"`,
+		`[a, b] => (a + b)`,
+		`[first, ...others] if (first > 10) => others`,
+		`{"type": "x", "size": [w, _], ...rest} => rest`,
+		`null => false`,
+		`_ => "other"`,
+	}
+	if len(exp.Arms) != len(expectedArms) {
+		t.Fatalf("wrong number of arms. expected=%d, got=%d", len(expectedArms), len(exp.Arms))
+	}
+	for i, arm := range expectedArms {
+		if exp.Arms[i].String() != arm {
+			t.Errorf("exp.Arms[%d] wrong. expected=%q, got=%q", i, arm, exp.Arms[i].String())
+		}
+	}
+
+	if lit, ok := exp.Arms[1].Pattern.(*ast.LiteralPattern).Value.(*ast.IntegerLiteral); !ok || lit.Value != -1 {
+		t.Errorf("second arm is not the integer -1. got=%v", exp.Arms[1].Pattern)
+	}
+	if _, ok := exp.Arms[6].Pattern.(*ast.WildcardPattern); !ok {
+		t.Errorf("last arm not *ast.WildcardPattern. got=%T", exp.Arms[6].Pattern)
+	}
+	if len(p.Warnings()) != 0 {
+		t.Errorf("unexpected warnings: %v", p.Warnings())
+	}
+}
+
+func TestInvalidMatches(t *testing.T) {
+	tests := []string{
+		"match (x) { 1 => }",
+		"match (x) { 1 if => 2 }",
+		"match () { 1 => 2 }",
+		"match (x) { - => 2 }",
+	}
+
+	for _, input := range tests {
+		p := parser.New(lexer.New(input))
+		program := p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+		_ = program.String() // Must not crash on the partial tree
+	}
+}
+
+func TestMatchBooleanExhaustiveness(t *testing.T) {
+	tests := []struct {
+		input           string // The match expression
+		expectedWarning string // The expected warning, empty if there should be none
+	}{
+		{`match (ok) { true => 1 }`, "match at 1:1 is not exhaustive: missing false"},
+		{`match (ok) { true => 1, false if x => 0 }`, "match at 1:1 is not exhaustive: missing false"},
+		{`match (ok) { false => 0 }`, "match at 1:1 is not exhaustive: missing true"},
+		{`match (ok) { true => 1, false => 0 }`, ""},
+		{`match (ok) { true => 1, other => 0 }`, ""},
+		{`match (n) { 0 => 1 }`, ""},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+		checkParserErrors(t, p)
+
+		warnings := p.Warnings()
+		if tt.expectedWarning == "" {
+			if len(warnings) != 0 {
+				t.Errorf("%s: unexpected warnings: %v", tt.input, warnings)
+			}
+			continue
+		}
+		if len(warnings) != 1 || warnings[0].Error() != tt.expectedWarning {
+			t.Errorf("%s: expected warning %q, got %v", tt.input, tt.expectedWarning, warnings)
+		}
+	}
+}
+
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string // The let statement
+		expected string // The statement as printed back
+	}{
+		{"let [a, b, ...rest] = arr;", "let [a, b, ...rest] = arr;"},
+		{"let {name, age: years} = person;", "let {name, age: years} = person;"},
+		{"let {\"id\": id, tags: [first, _]} = item;", "let {\"id\": id, tags: [first, _]} = item;"},
+		{"let [] = empty;", "let [] = empty;"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if stmt.Name != nil { // Destructuring does not use the simple-identifier fast path
+			t.Errorf("stmt.Name not nil. got=%q", stmt.Name)
+		}
+		if stmt.Pattern == nil {
+			t.Fatalf("stmt.Pattern is nil")
+		}
+		if stmt.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestLetStatementValues(t *testing.T) {
+	l := lexer.New("let x = a + b * 2;")
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.LetStatement)
+	if stmt.Pattern != nil {
+		t.Errorf("stmt.Pattern not nil for a plain identifier. got=%q", stmt.Pattern)
+	}
+	if stmt.Value.String() != "(a + (b * 2))" {
+		t.Errorf("stmt.Value wrong. got=%q", stmt.Value.String())
+	}
+}
+
+func TestFunctionParameterParsing(t *testing.T) {
+	tests := []struct {
+		input       string // The function literal
+		expected    string // The function literal as printed back
+		expectedMin int    // The expected number of required parameters
+		expectedMax int    // The expected number of parameters, -1 if variadic
+	}{
+		{"fn() { }", "fn() { }", 0, 0},
+		{"fn(x, y) { x + y; }", "fn(x, y) { (x + y) }", 2, 2},
+		{"fn(x, y = 10) { return x * y; }", "fn(x, y = 10) { return (x * y); }", 1, 2},
+		{"fn(x, y = x + 1, ...rest) { rest; }", "fn(x, y = (x + 1), ...rest) { rest }", 1, -1},
+		{"fn(...all) { }", "fn(...all) { }", 0, -1},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		fn, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.FunctionLiteral. got=%T", stmt.Expression)
+		}
+		if fn.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, fn.String())
+		}
+		if min, max := fn.Arity(); min != tt.expectedMin || max != tt.expectedMax {
+			t.Errorf("%s: arity wrong. expected=%d-%d, got=%d-%d", tt.input, tt.expectedMin, tt.expectedMax, min, max)
+		}
+	}
+}
+
+func TestInvalidFunctionParameters(t *testing.T) {
+	tests := []string{
+		"fn(...rest, x) { }",
+		"fn(x = 1, y) { }",
+		"fn(1) { }",
+		"f(x: 1, 2);",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestCallArgumentChecking(t *testing.T) {
+	tests := []struct {
+		input         string // A function bound with let and a call to it
+		expectedError string // The expected CheckArguments error, empty if the call is valid
+	}{
+		{"let f = fn(x, y = 10) { }; f(1);", ""},
+		{"let f = fn(x, y = 10) { }; f(y: 2, x: 1);", ""},
+		{"let f = fn(x, y = 10) { }; f(1, 2, 3);", "f expects 1-2 arguments, got 3"},
+		{"let f = fn(x, y = 10) { }; f();", "f expects 1-2 arguments, got 0"},
+		{"let f = fn(x) { }; f(1, 2);", "f expects 1 argument, got 2"},
+		{"let f = fn(x, y) { }; f(1);", "f expects 2 arguments, got 1"},
+		{"let f = fn(x, ...rest) { }; f();", "f expects at least 1 argument, got 0"},
+		{"let f = fn(x, ...rest) { }; f(1, 2, 3, 4);", ""},
+		{"let f = fn(x, y = 10) { }; f(1, z: 2);", "f has no parameter named z"},
+		{"let f = fn(x, y = 10) { }; f(1, x: 2);", "f got more than one value for parameter x"},
+		{"let f = fn(x, y = 10) { }; f(y: 2);", "f is missing an argument for parameter x"},
+		{"let f = fn(x, y) { }; f(...xs);", ""},
+		{"let f = fn(x, y) { }; f(...xs, z: 1);", "f has no parameter named z"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+		call := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+
+		err := fn.CheckArguments(call.Arguments)
+		if tt.expectedError == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %q", tt.input, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.expectedError {
+			t.Errorf("%s: expected error %q, got %v", tt.input, tt.expectedError, err)
+		}
+	}
+}
+
+func TestStructParsing(t *testing.T) {
+	input := `
+struct Point { x, y }
+let p = Point{x: 1, y: 2 + 3};
+p.x = p.y * 2;
+`
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 3 {
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d", len(program.Statements))
+	}
+
+	decl, ok := program.Statements[0].(*ast.StructDecl)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.StructDecl. got=%T", program.Statements[0])
+	}
+	if decl.String() != "struct Point { x, y }" {
+		t.Errorf("decl wrong. got=%q", decl.String())
+	}
+
+	let := program.Statements[1].(*ast.LetStatement)
+	if _, ok := let.Value.(*ast.StructLiteral); !ok {
+		t.Fatalf("let.Value is not *ast.StructLiteral. got=%T", let.Value)
+	}
+	if let.Value.String() != "Point{x: 1, y: (2 + 3)}" {
+		t.Errorf("struct literal wrong. got=%q", let.Value.String())
+	}
+
+	update := program.Statements[2].(*ast.ExpressionStatement)
+	if _, ok := update.Expression.(*ast.AssignExpression); !ok {
+		t.Fatalf("update is not *ast.AssignExpression. got=%T", update.Expression)
+	}
+	if update.String() != "(p.x = (p.y * 2))" {
+		t.Errorf("field update wrong. got=%q", update.String())
+	}
+}
+
+func TestInvalidStructs(t *testing.T) {
+	tests := []string{
+		"struct Point { x, x }",
+		"struct { x }",
+		"x = 1;",
+		"a?.b = 1;",
+		"f(){x: 1};",
+		"a. = 1;",
+		"a ?. = 1;",
+		"p.x = ;",
+		"a.b = 1 + ;",
+		"P{x: impl};",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+		_ = program.String() // Must not crash on the partial tree
+	}
+}
+
+func TestTraitAndImplParsing(t *testing.T) {
+	input := `
+trait Shape {
+	fn area(self)
+	fn describe(self) { "a shape"; }
+}
+
+impl Point {
+	fn len(self) { self.x * self.x + self.y * self.y; }
+}
+
+impl Shape for Point {
+	fn area(self) { 0; }
+}
+
+p.len();
+`
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("program.Statements does not contain 4 statements. got=%d", len(program.Statements))
+	}
+
+	trait, ok := program.Statements[0].(*ast.TraitDecl)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.TraitDecl. got=%T", program.Statements[0])
+	}
+	if len(trait.Methods) != 2 || trait.Methods[0].Body != nil || trait.Methods[1].Body == nil {
+		t.Errorf("trait methods wrong. got=%q", trait.String())
+	}
+
+	inherent := program.Statements[1].(*ast.ImplDecl)
+	if inherent.Trait != nil || inherent.Type.Value != "Point" {
+		t.Errorf("inherent impl wrong. got=%q", inherent.String())
+	}
+
+	impl := program.Statements[2].(*ast.ImplDecl)
+	if impl.String() != "impl Shape for Point { fn area(self) { 0 } }" {
+		t.Errorf("trait impl wrong. got=%q", impl.String())
+	}
+
+	call := program.Statements[3].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	if _, ok := call.Function.(*ast.MemberExpression); !ok {
+		t.Errorf("method call is not on a *ast.MemberExpression. got=%T", call.Function)
+	}
+}
+
+func TestImplMustSatisfyTrait(t *testing.T) {
+	trait := "trait Shape { fn area(self) fn name(self) { \"shape\"; } }\n"
+
+	tests := []struct {
+		input         string // An impl of Shape
+		expectedError string // The expected error, empty if the impl is valid
+	}{
+		{"impl Shape for Sq { fn area(self) { 1; } }", ""},
+		{"impl Shape for Sq { fn area(self) { 1; } fn name(self) { 2; } }", ""},
+		{"impl Shape for Sq { }", "impl Shape for Sq at 2:1: missing method area"},
+		{"impl Shape for Sq { fn area(self, x) { 1; } }", "impl Shape for Sq at 2:1: method area takes 2 parameters, trait Shape declares 1"},
+		{"impl Shape for Sq { fn area(self) { 1; } fn extra(self) { } }", "impl Shape for Sq at 2:1: method extra is not part of trait Shape"},
+		{"impl Drawable for Sq { }", "impl Drawable for Sq at 2:1: unknown trait Drawable"},
+	}
+
+	for _, prefix := range []string{"", "export "} { // An exported trait is implemented the same way
+		for _, tt := range tests {
+			l := lexer.New(prefix + trait + tt.input)
+			p := parser.New(l)
+			p.ParseProgram()
+
+			errs := p.Errors()
+			if tt.expectedError == "" {
+				if len(errs) != 0 {
+					t.Errorf("%s%s: unexpected errors %v", prefix, tt.input, errs)
+				}
+				continue
+			}
+			if len(errs) != 1 || errs[0].Error() != tt.expectedError {
+				t.Errorf("%s%s: expected error %q, got %v", prefix, tt.input, tt.expectedError, errs)
+			}
+		}
+	}
+}
+
+func TestEnumParsing(t *testing.T) {
+	input := `
+enum Result { Ok(v), Err(e) }
+enum State { Pending, Done }
+let r = Ok(5);
+match (r) {
+	Ok(v) => v,
+	Result.Err(_) => 0,
+	Pending => 1,
+	other => 2,
+}
+`
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("program.Statements does not contain 4 statements. got=%d", len(program.Statements))
+	}
+
+	decl, ok := program.Statements[0].(*ast.EnumDecl)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.EnumDecl. got=%T", program.Statements[0])
+	}
+	if decl.String() != "enum Result { Ok(v), Err(e) }" {
+		t.Errorf("decl wrong. got=%q", decl.String())
+	}
+
+	match := program.Statements[3].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+	expectedPatterns := []string{"Ok(v)", "Result.Err(_)", "Pending", "other"}
+	for i, expected := range expectedPatterns {
+		if match.Arms[i].Pattern.String() != expected {
+			t.Errorf("match.Arms[%d] wrong. expected=%q, got=%q", i, expected, match.Arms[i].Pattern.String())
+		}
+	}
+
+	if _, ok := match.Arms[2].Pattern.(*ast.VariantPattern); !ok { // A declared bare variant is not a binding
+		t.Errorf("Pending is not *ast.VariantPattern. got=%T", match.Arms[2].Pattern)
+	}
+	if _, ok := match.Arms[3].Pattern.(*ast.BindingPattern); !ok {
+		t.Errorf("other is not *ast.BindingPattern. got=%T", match.Arms[3].Pattern)
+	}
+}
+
+func TestVariantsDeclaredLater(t *testing.T) {
+	input := `
+let f = fn(s) { match (s) { Pending => 1, [Done] => 2, {state: Done} => 3, {Pending} => 4, other => 5 } };
+let [Done, rest] = pair;
+export enum State { Pending, Done }
+`
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	if len(p.Warnings()) != 0 {
+		t.Errorf("unexpected warnings: %v", p.Warnings())
+	}
+
+	fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	match := fn.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+
+	if _, ok := match.Arms[0].Pattern.(*ast.VariantPattern); !ok {
+		t.Errorf("Pending is not *ast.VariantPattern. got=%T", match.Arms[0].Pattern)
+	}
+	if _, ok := match.Arms[1].Pattern.(*ast.ArrayPattern).Elements[0].(*ast.VariantPattern); !ok {
+		t.Errorf("Done in [Done] is not *ast.VariantPattern")
+	}
+	if _, ok := match.Arms[2].Pattern.(*ast.HashPattern).Pairs[0].Value.(*ast.VariantPattern); !ok {
+		t.Errorf("Done in {state: Done} is not *ast.VariantPattern")
+	}
+	if _, ok := match.Arms[3].Pattern.(*ast.HashPattern).Pairs[0].Value.(*ast.BindingPattern); !ok { // The shorthand always binds
+		t.Errorf("Pending in {Pending} is not *ast.BindingPattern")
+	}
+	if _, ok := match.Arms[4].Pattern.(*ast.BindingPattern); !ok {
+		t.Errorf("other is not *ast.BindingPattern. got=%T", match.Arms[4].Pattern)
+	}
+
+	let := program.Statements[1].(*ast.LetStatement).Pattern.(*ast.ArrayPattern)
+	if _, ok := let.Elements[0].(*ast.VariantPattern); !ok {
+		t.Errorf("Done in let [Done, rest] is not *ast.VariantPattern. got=%T", let.Elements[0])
+	}
+	if _, ok := let.Elements[1].(*ast.BindingPattern); !ok {
+		t.Errorf("rest in let [Done, rest] is not *ast.BindingPattern. got=%T", let.Elements[1])
+	}
+}
+
+func TestUnreachableMatchArms(t *testing.T) {
+	tests := []struct {
+		input    string // A match
+		expected string // The expected warning, empty if there should be none
+	}{
+		{"match (s) { Pending => 1, Done => 2 }", "match arm at 1:27 is unreachable: Pending at 1:13 matches every value"},
+		{"match (s) { _ => 1, 2 => 2 }", "match arm at 1:21 is unreachable: _ at 1:13 matches every value"},
+		{"match (s) { n if n > 1 => 1, n => 2 }", ""},
+		{"match (s) { 1 => 1, n => 2 }", ""},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		p.ParseProgram()
+		checkParserErrors(t, p)
+
+		warnings := p.Warnings()
+		if tt.expected == "" {
+			if len(warnings) != 0 {
+				t.Errorf("%s: unexpected warnings %v", tt.input, warnings)
+			}
+			continue
+		}
+		if len(warnings) != 1 || warnings[0].Error() != tt.expected {
+			t.Errorf("%s: expected warning %q, got %v", tt.input, tt.expected, warnings)
+		}
+	}
+}
+
+func TestInvalidEnums(t *testing.T) {
+	tests := []string{
+		"enum A { X, X }",
+		"enum A { X } enum B { X }",
+		"enum Result { Ok(v) } match (r) { Ok(a, b) => a }",
+		"enum Result { Ok(v) } enum State { Done } match (r) { State.Ok(v) => v }",
+		"match (r) { Ok(a, b) => a } enum Result { Ok(v) }", // Checked once the enum is known
+		"match (r) { State.Ok(v) => v } enum Result { Ok(v) } enum State { Done }",
+		"match (r) { Ok => 1 } export enum Result { Ok(v) }",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestThrowAndTryParsing(t *testing.T) {
+	tests := []struct {
+		input    string // The statement
+		expected string // The statement as printed back
+	}{
+		{`throw "not found";`, `throw "not found";`},
+		{`throw Err(code);`, `throw Err(code);`},
+		{"try { risky(); } catch (e) { e; }", "try { risky() } catch (e) { e }"},
+		{"try { risky(); } finally { cleanup(); }", "try { risky() } finally { cleanup() }"},
+		{"try { risky(); } catch { 0; } finally { done(); }", "try { risky() } catch { 0 } finally { done() }"},
+		{"let v = try { parse(s); } catch (e) { null; };", "let v = try { parse(s) } catch (e) { null };"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("%s: program.Statements does not contain 1 statement. got=%d", tt.input, len(program.Statements))
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestInvalidTry(t *testing.T) {
+	tests := []string{
+		"try { risky(); }",
+		"try { risky(); } catch (1) { }",
+		"throw;",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestImportAndExportParsing(t *testing.T) {
+	input := `
+import "./utils.bro" as utils;
+/// The default port.
+export let port = 8080;
+export struct Point { x, y }
+`
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 3 {
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d", len(program.Statements))
+	}
+
+	imp, ok := program.Statements[0].(*ast.ImportStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ImportStatement. got=%T", program.Statements[0])
+	}
+	if imp.Path.Value != "./utils.bro" || imp.Alias.Value != "utils" {
+		t.Errorf("import wrong. got=%q", imp.String())
+	}
+
+	export, ok := program.Statements[1].(*ast.ExportStatement)
+	if !ok {
+		t.Fatalf("program.Statements[1] is not *ast.ExportStatement. got=%T", program.Statements[1])
+	}
+	if export.String() != "export let port = 8080;" {
+		t.Errorf("export wrong. got=%q", export.String())
+	}
+	if doc := export.Declaration.(*ast.LetStatement).Doc; doc != "The default port." { // The doc comment goes through export
+		t.Errorf("export doc wrong. got=%q", doc)
+	}
+
+	for _, input := range []string{`import "./a.bro";`, `import utils;`, `export 5;`} {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestGeneratorParsing(t *testing.T) {
+	tests := []struct {
+		input     string // A let binding a function
+		expected  string // The function as printed back
+		generator bool   // Whether the function is a generator
+	}{
+		{"let count = fn(n) { yield n; yield; };", "fn(n) { yield n yield }", true},
+		{"let echo = fn() { let x = yield 1 + 2; x; };", "fn() { let x = yield (1 + 2); x }", true},
+		{"let plain = fn() { 1; };", "fn() { 1 }", false},
+		{"let outer = fn() { fn() { yield 1; }; };", "fn() { fn() { yield 1 } }", false}, // The yield belongs to the inner function
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		fn, ok := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("%s: value is not *ast.FunctionLiteral", tt.input)
+		}
+		if fn.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, fn.String())
+		}
+		if fn.Generator != tt.generator {
+			t.Errorf("%s: fn.Generator wrong. expected=%t, got=%t", tt.input, tt.generator, fn.Generator)
+		}
+	}
+
+	p := parser.New(lexer.New("trait Iterable { fn items(self) } struct Range { from, to } impl Iterable for Range { fn items(self) { yield self.from; } }"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	if method := program.Statements[2].(*ast.ImplDecl).Methods[0]; !method.Generator {
+		t.Errorf("method %s is not a generator", method.Name.Value)
+	}
+
+	for _, input := range []string{"yield 1;", "let x = yield;"} {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestSpawnAndSelectParsing(t *testing.T) {
+	tests := []struct {
+		input    string // The expression
+		expected string // The expression as printed back
+	}{
+		{"spawn check(url);", "spawn check(url)"},
+		{"spawn check(url) + 1;", "(spawn check(url) + 1)"},
+		{"let t = spawn fn(x) { x; }(1);", "let t = spawn fn(x) { x }(1);"},
+		{"select { recv(results) as r => r, send(jobs, next) => \"queued\", _ => \"busy\", }",
+			"select { recv(results) as r => r, send(jobs, next) => \"queued\", _ => \"busy\" }"},
+		{"select { recv(done) => null }", "select { recv(done) => null }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	invalid := []string{
+		"spawn 5;",
+		"spawn f;",
+		"select { }",
+		"select { recv(a) => 1, _ => 2, _ => 3 }",
+		"select { send(ch, 1) as v => v }",
+		"select { print(x) => 1 }",
+		"select { recv(a, b) => 1 }",
+	}
+	for _, input := range invalid {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestTailCallMarking(t *testing.T) {
+	tests := []struct {
+		input string          // A let binding a function
+		tail  map[string]bool // Whether each call in the function, printed, is a tail call
+	}{
+		{"let countdown = fn(n) { log(n); countdown(n - 1); };", map[string]bool{"log(n)": false, "countdown((n - 1))": true}},
+		{"let f = fn(n) { return g(n); h(n); };", map[string]bool{"g(n)": true, "h(n)": true}},
+		{"let f = fn(n) { g(n) + 1; };", map[string]bool{"g(n)": false}},
+		{"let f = fn(n) { match (n) { 0 => done(), _ => f(n - 1) }; };", map[string]bool{"done()": true, "f((n - 1))": true}},
+		{"let f = fn(n) { try { f(n); } catch { g(n); }; };", map[string]bool{"f(n)": false, "g(n)": false}},
+		{"let f = fn(n) { let x = g(n); x; };", map[string]bool{"g(n)": false}},
+		{"let f = fn(n) { spawn g(n); };", map[string]bool{"g(n)": false}},
+		{"let f = n => f(n - 1);", map[string]bool{"f((n - 1))": true}},
+		{"let f = (a, b) => g(a) + b;", map[string]bool{"g(a)": false}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		seen := 0
+		ast.Modify(program, func(node ast.Node) ast.Node {
+			if call, ok := node.(*ast.CallExpression); ok {
+				seen++
+				if expected, ok := tt.tail[call.String()]; !ok || call.Tail != expected {
+					t.Errorf("%s: %s Tail=%t, expected=%t", tt.input, call.String(), call.Tail, expected)
+				}
+			}
+			return node
+		})
+		if seen != len(tt.tail) {
+			t.Errorf("%s: expected %d calls, got %d", tt.input, len(tt.tail), seen)
+		}
+	}
+}
+
+func TestTypeAnnotationParsing(t *testing.T) {
+	tests := []struct {
+		input    string // The annotated statement
+		expected string // The statement as printed back
+	}{
+		{"let x: int = 5;", "let x: int = 5;"},
+		{"let n: null = null;", "let n: null = null;"},
+		{"let f = fn(a: int, b: string = \"x\", ...rest: int) -> bool { true; };", "let f = fn(a: int, b: string = \"x\", ...rest: int) -> bool { true };"},
+		{"let apply: fn(fn(int) -> int, int) -> int = fn(f, x) { f(x); };", "let apply: fn(fn(int) -> int, int) -> int = fn(f, x) { f(x) };"},
+		{"trait Shape { fn area(self) -> int }", "trait Shape { fn area(self) -> int }"},
+		{"let d = a - b;", "let d = (a - b);"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	for _, input := range []string{"let x: = 5;", "let x: 5 = 5;", "let f = fn(a:) { a; };", "let f = fn() -> { 1; };", "let t: fn(int) = f;", "macro(x: int) { x; }"} {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestBigIntegerLiteral(t *testing.T) {
+	tests := []struct {
+		input string // The literal
+		value int64  // The expected Value
+		big   string // The expected Big, empty if it should be nil
+	}{
+		{"9223372036854775807;", 9223372036854775807, ""},
+		{"9223372036854775808;", 0, "9223372036854775808"},
+		{"123456789012345678901234567890;", 0, "123456789012345678901234567890"},
+		{"010;", 10, ""}, // Decimal, not octal
+		{"09;", 9, ""},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		lit, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("%s: exp not *ast.IntegerLiteral", tt.input)
+		}
+		if lit.Value != tt.value {
+			t.Errorf("%s: Value wrong. expected=%d, got=%d", tt.input, tt.value, lit.Value)
+		}
+		if (lit.Big == nil) != (tt.big == "") || (lit.Big != nil && lit.Big.String() != tt.big) {
+			t.Errorf("%s: Big wrong. expected=%q, got=%v", tt.input, tt.big, lit.Big)
+		}
+		if lit.String() != strings.TrimSuffix(tt.input, ";") {
+			t.Errorf("%s: printed as %s", tt.input, lit.String())
+		}
+	}
+}
+
+func TestInvalidIntegerLiterals(t *testing.T) {
+	tests := []struct {
+		input    string // The program
+		expected string // The only error it should produce
+	}{
+		{"let a = 0x1F;", `could not parse "0x1F" as integer at 1:9`},
+		{"let b = 0b101;", `could not parse "0b101" as integer at 1:9`},
+		{"let c = 12abc;", `could not parse "12abc" as integer at 1:9`},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errs := p.Errors()
+		if len(errs) != 1 || errs[0].Error() != tt.expected {
+			t.Errorf("%s: expected error %q, got %v", tt.input, tt.expected, errs)
+		}
+	}
+}
+
+func TestSliceAndRangeParsing(t *testing.T) {
+	tests := []struct {
+		input    string // The expression
+		expected string // The expression as printed back
+	}{
+		{"arr[1:3]", "(arr[1:3])"},
+		{"arr[:-1]", "(arr[:(-1)])"},
+		{"str[::2]", "(str[::2])"},
+		{"arr[1:10:2]", "(arr[1:10:2])"},
+		{"arr[1:]", "(arr[1:])"},
+		{"arr[:]", "(arr[:])"},
+		{"arr[1:10:]", "(arr[1:10])"},
+		{"a?.[i:]", "(a?.[i:])"},
+		{"arr[i]", "(arr[i])"},
+		{"0..10", "(0..10)"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := parser.New(lexer.New("arr[:-1];")).ParseProgram()
+	slice, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.SliceExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.SliceExpression. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	if slice.Start != nil || slice.End == nil || slice.Step != nil {
+		t.Errorf("wrong parts. start=%v, end=%v, step=%v", slice.Start, slice.End, slice.Step)
+	}
+
+	for _, input := range []string{"arr[1:2:3:4];", "arr[1:2;", "arr[::];x[:;"} {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestArrowAndPipelineParsing(t *testing.T) {
+	tests := []struct {
+		input    string // The expression
+		expected string // The expression as printed back
+	}{
+		{"x => x * 2", "x => (x * 2)"},
+		{"(x) => x", "(x) => x"},
+		{"(a, b) => a + b", "(a, b) => (a + b)"},
+		{"() => 42", "() => 42"},
+		{"map(xs, x => x.id)", "map(xs, x => x.id)"},
+		{"a => b => a + b", "a => b => (a + b)"},
+		{"data |> filter(isValid) |> map(x => x.id)", "((data |> filter(isValid)) |> map(x => x.id))"},
+		{"a ?? b |> f", "((a ?? b) |> f)"},
+		{"x => x |> f", "x => (x |> f)"},
+		{"match (v) { n if ok => n, n if any(xs, x => x == n) => 0, _ => 1 }", "match (v) { n if ok => n, n if any(xs, x => (x == n)) => 0, _ => 1 }"},
+		{"match (v) { n if (ok) => n }", "match (v) { n if ok => n }"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := parser.New(lexer.New("data |> filter(isValid) |> map(x => x.id) |> len;")).ParseProgram()
+	pipeline, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.PipelineExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.PipelineExpression. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	if desugared := pipeline.Desugar().String(); desugared != "len(map(filter(data, isValid), x => x.id))" {
+		t.Errorf("wrong desugaring. got=%q", desugared)
+	}
+
+	program = parser.New(lexer.New("let f = fn(x) { x |> g(1); };")).ParseProgram()
+	body := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral).Body
+	pipeline = body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.PipelineExpression)
+	if !pipeline.Tail || !pipeline.Desugar().Tail {
+		t.Errorf("a pipeline in tail position is not a tail call")
+	}
+
+	for _, input := range []string{"(a, 1) => a;", "(a, b);", "() + 1;", "x |> ;", "let f = fn() { x => yield x; };"} {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestSpreadParsing(t *testing.T) {
+	tests := []struct {
+		input    string // The expression
+		expected string // The expression as printed back
+	}{
+		{"f(...args)", "f(...args)"},
+		{"f(a, ...rest, b)", "f(a, ...rest, b)"},
+		{"f(...a.b, x: 1)", "f(...a.b, x: 1)"},
+		{"[]", "[]"},
+		{"[1, a + b, ...xs]", "[1, (a + b), ...xs]"},
+		{"[...a, ...b]", "[...a, ...b]"},
+		{"[...xs |> f]", "[...(xs |> f)]"},
+		{"{}", "{}"},
+		{`{...defaults, "k": 1}`, `{...defaults, "k": 1}`},
+		{"{port, host: h, 1: [x]}", "{port, host: h, 1: [x]}"},
+		{"[1, 2][0]", "([1, 2][0])"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := parser.New(lexer.New(`{...defaults, "k": 1};`)).ParseProgram()
+	hash, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.HashLiteral. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	if spread, ok := hash.Pairs[0].Value.(*ast.SpreadElement); !ok || hash.Pairs[0].Key != nil || spread.Value.String() != "defaults" {
+		t.Errorf("hash.Pairs[0] is not a spread of defaults. got=%s", hash.Pairs[0])
+	}
+
+	for _, input := range []string{"f(x: 1, ...rest);", "[...];", "[1 2];", "{a + b: 1};", `{"k" 1};`, "{...};"} {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", input)
+		}
+	}
+}
+
+func TestMissingOperands(t *testing.T) {
+	tests := []struct {
+		input    string // A statement with an operand missing
+		expected string // The first error
+	}{
+		{"let z = 1 + ;", "no prefix parse function for ; at 1:13"},
+		{"let y = a || ;", "no prefix parse function for ; at 1:14"},
+		{"let x = !;", "no prefix parse function for ; at 1:10"},
+		{"a |> ]", "no prefix parse function for ] at 1:6"},
+		{"* 2;", "no prefix parse function for * at 1:1"},
+		{"let u = fn(a = 1 +) { a };", "no prefix parse function for ) at 1:19"},
+		{"match (1 +) { _ => 1 };", "no prefix parse function for ) at 1:11"},
+		{"f(1 +) = 2;", "no prefix parse function for ) at 1:6"},
+		{"a[1 +] = 2;", "no prefix parse function for ] at 1:6"},
+		{"f(impl);", "no prefix parse function for IMPL at 1:3"},
+		{"f(a: impl);", "no prefix parse function for IMPL at 1:6"},
+		{"[impl];", "no prefix parse function for IMPL at 1:2"},
+		{"{1: impl};", "no prefix parse function for IMPL at 1:5"},
+		{`"a${impl}";`, "no prefix parse function for IMPL at 1:5"},
+		{"a[impl];", "no prefix parse function for IMPL at 1:3"},
+		{"a[impl:1];", "no prefix parse function for IMPL at 1:3"},
+		{"a[1:impl];", "no prefix parse function for IMPL at 1:5"},
+		{"a[1:2:impl];", "no prefix parse function for IMPL at 1:7"},
+		{"a[1:2 +];", "no prefix parse function for ] at 1:8"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%s: expected a parser error", tt.input)
+			continue
+		}
+		if p.Errors()[0].Error() != tt.expected {
+			t.Errorf("%s: expected=%q, got=%q", tt.input, tt.expected, p.Errors()[0].Error())
+		}
+		_ = program.String() // Must not crash on the partial tree
+	}
+}
+
+func TestIllegalTokens(t *testing.T) {
+	tests := []struct {
+		input    string // A program the lexer cannot make sense of
+		expected string // The only error it should produce
+	}{
+		{"let x = 5; /* never closed", "unterminated block comment at 1:12"},
+		{"let x = 5 /* never closed", "unterminated block comment at 1:11"},
+		{`let s = "abc`, "unterminated string at 1:9"},
+		{"let a = 1 ? 2;", `illegal token "?" at 1:11`},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errs := p.Errors()
+		if len(errs) != 1 || errs[0].Error() != tt.expected {
+			t.Errorf("%s: expected error %q, got %v", tt.input, tt.expected, errs)
+		}
+	}
+}
+
+func TestOperatorPrecedenceParsing(t *testing.T) {
+	tests := []struct {
+		input    string // The input expression
+		expected string // The fully parenthesized expression
+	}{
+		{"a + b * c", "(a + (b * c))"},
+		{"a * b % c", "((a * b) % c)"},
+		{"a <= b == c >= d", "((a <= b) == (c >= d))"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c && d", "((a && b) || (c && d))"},
+		{"a == b && c != d", "((a == b) && (c != d))"},
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"a * b ** c", "(a * (b ** c))"},
+		{"a ** b * c", "((a ** b) * c)"},
+		{"-2 ** 2", "(-(2 ** 2))"},
+		{"~a ** b * c", "((~(a ** b)) * c)"},
+		{"2 ** -1", "(2 ** (-1))"},
+		{"-a[0] ** 2", "(-((a[0]) ** 2))"},
+		{"a + b - c", "((a + b) - c)"},
+		{"a | b & c", "(a | (b & c))"},
+		{"a ^ b | c", "((a ^ b) | c)"},
+		{"a + b << 2", "(a + (b << 2))"},
+		{"a << 1 >> 2", "((a << 1) >> 2)"},
+		{"a & 1 == 1", "((a & 1) == 1)"},
+		{"~a & b", "((~a) & b)"},
+		{"a == 1 && b | 4 != 0", "((a == 1) && ((b | 4) != 0))"},
+		{"a ?? b || c", "(a ?? (b || c))"},
+		{"a ?? b ?? null", "((a ?? b) ?? null)"},
+		{"a?.b?.c", "a?.b?.c"},
+		{"a?.[i + 1] * 2", "((a?.[(i + 1)]) * 2)"},
+		{"a[0][1]", "((a[0])[1])"},
+		{"~a?.b", "(~a?.b)"},
+		{"a?.b ?? 8080", "(a?.b ?? 8080)"},
+		{"a + f(b * c, d)", "(a + f((b * c), d))"},
+		{"f(x: 1, y: a + b)", "f(x: 1, y: (a + b))"},
+		{"f(g(1))[0]", "(f(g(1))[0])"},
+		{"a.b.c", "a.b.c"},
+		{"~a.b", "(~a.b)"},
+		{"a.b(c)[0] * 2", "((a.b(c)[0]) * 2)"},
+		{"a.x = b.y = c ?? 1", "(a.x = (b.y = (c ?? 1)))"},
+		{"-a * b", "((-a) * b)"},
+		{"!a && b", "((!a) && b)"},
+		{"a || !b", "(a || (!b))"},
+		{"!!a == b", "((!(!a)) == b)"},
+		{"(a + b) * c", "((a + b) * c)"},
+		{"a[1:n - 1]", "(a[1:(n - 1)])"},
+		{"0..n + 1 < m", "((0..(n + 1)) < m)"},
+		{"(0..10).step(2)", "(0..10).step(2)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected { // Checks the grouping of the operators
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
 /*
 Tests the let statement
 */
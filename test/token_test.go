@@ -17,7 +17,7 @@ func TestNextToken(t *testing.T) {
 	x + y;
 	};
 	let result = add(five, ten);
-	!-/*5;
+	!-/ *5;
 	5 < 10 > 5;
 	if (5 < 10) {
 	return true;
@@ -81,7 +81,7 @@ func TestNextToken(t *testing.T) {
 		// )
 		{token.RPAREN, ")"},
 		{token.SEMICOLON, ";"},
-		// !-/*5;
+		// !-/ *5;
 		{token.BANG, "!"},
 		{token.MINUS, "-"},
 		{token.SLASH, "/"},
@@ -158,3 +158,465 @@ func TestNextToken(t *testing.T) {
 
 	}
 }
+
+// TestNextTokenLogicalOperators tests the two-character operators and the modulo/power operators
+func TestNextTokenLogicalOperators(t *testing.T) {
+	input := `a <= b >= c && d || e % f ** g;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.LT_EQ, "<="},
+		{token.IDENT, "b"},
+		{token.GT_EQ, ">="},
+		{token.IDENT, "c"},
+		{token.AND, "&&"},
+		{token.IDENT, "d"},
+		{token.OR, "||"},
+		{token.IDENT, "e"},
+		{token.PERCENT, "%"},
+		{token.IDENT, "f"},
+		{token.POWER, "**"},
+		{token.IDENT, "g"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNextTokenBitwiseOperators tests that the bitwise operators are told apart from && and ||
+func TestNextTokenBitwiseOperators(t *testing.T) {
+	input := `a & b | c ^ ~d << 2 >> 1 && e || f;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.BIT_AND, "&"},
+		{token.IDENT, "b"},
+		{token.BIT_OR, "|"},
+		{token.IDENT, "c"},
+		{token.BIT_XOR, "^"},
+		{token.BIT_NOT, "~"},
+		{token.IDENT, "d"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.INT, "2"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.INT, "1"},
+		{token.AND, "&&"},
+		{token.IDENT, "e"},
+		{token.OR, "||"},
+		{token.IDENT, "f"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNextTokenComments tests that comments are skipped and doc comments are kept
+func TestNextTokenComments(t *testing.T) {
+	input := `// a line comment
+	let x = 5; // trailing comment
+	/* a block /* nested */ comment */
+	/// Adds two numbers.
+	///   Indentation after the first space is kept.
+	//// not a doc comment
+	let add = x / y;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.DOC_COMMENT, "Adds two numbers."},
+		{token.DOC_COMMENT, "  Indentation after the first space is kept."},
+		{token.LET, "let"},
+		{token.IDENT, "add"},
+		{token.ASSIGN, "="},
+		{token.IDENT, "x"},
+		{token.SLASH, "/"},
+		{token.IDENT, "y"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNextTokenUnterminatedBlockComment tests that an unclosed block comment is reported as illegal
+func TestNextTokenUnterminatedBlockComment(t *testing.T) {
+	l := lexer.New("let x = 5; /* never /* closed */")
+
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if tok.Type == token.ILLEGAL {
+			return
+		}
+	}
+	t.Fatalf("expected an ILLEGAL token for the unterminated block comment")
+}
+
+// TestNextTokenUnicodeIdentifiers tests identifiers with digits and non-ASCII letters
+func TestNextTokenUnicodeIdentifiers(t *testing.T) {
+	input := "let x1 = café + 名前2 🚀 _tmp9;"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "x1"},
+		{token.ASSIGN, "="},
+		{token.IDENT, "café"},
+		{token.PLUS, "+"},
+		{token.IDENT, "名前2"},
+		{token.ILLEGAL, "🚀"},
+		{token.IDENT, "_tmp9"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNextTokenPositions tests that line and column are counted in characters, not bytes
+func TestNextTokenPositions(t *testing.T) {
+	input := "let café = 1;\n  é + x;"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"let", 1, 1},
+		{"café", 1, 5},
+		{"=", 1, 10},
+		{"1", 1, 12},
+		{";", 1, 13},
+		{"é", 2, 3},
+		{"+", 2, 5},
+		{"x", 2, 7},
+		{";", 2, 8},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d", i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+// TestNextTokenStrings tests plain strings, escapes and the tokens around ${...} parts
+func TestNextTokenStrings(t *testing.T) {
+	input := `"plain \"quoted\" \$5" "Hi ${name}, ${a + b}!" "x ${ {y} } ${"in ${z}"}"`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING, `plain "quoted" $5`},
+		// "Hi ${name}, ${a + b}!"
+		{token.INTERP_HEAD, "Hi "},
+		{token.IDENT, "name"},
+		{token.INTERP_MIDDLE, ", "},
+		{token.IDENT, "a"},
+		{token.PLUS, "+"},
+		{token.IDENT, "b"},
+		{token.INTERP_TAIL, "!"},
+		// "x ${ {y} } ${"in ${z}"}" - braces and strings nest inside ${}
+		{token.INTERP_HEAD, "x "},
+		{token.LBRACE, "{"},
+		{token.IDENT, "y"},
+		{token.RBRACE, "}"},
+		{token.INTERP_MIDDLE, " "},
+		{token.INTERP_HEAD, "in "},
+		{token.IDENT, "z"},
+		{token.INTERP_TAIL, ""},
+		{token.INTERP_TAIL, ""},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNextTokenNullSafeOperators tests null and the ?. and ?? operators
+func TestNextTokenNullSafeOperators(t *testing.T) {
+	input := `let port = config?.server?.[0] ?? null;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "port"},
+		{token.ASSIGN, "="},
+		{token.IDENT, "config"},
+		{token.OPTIONAL_CHAIN, "?."},
+		{token.IDENT, "server"},
+		{token.OPTIONAL_CHAIN, "?."},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.RBRACKET, "]"},
+		{token.NULLISH, "??"},
+		{token.NULL, "null"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNextTokenMatch tests the tokens used by match expressions and patterns
+func TestNextTokenMatch(t *testing.T) {
+	input := `match (v) { {"k": x, ...rest} => x, _ => 0 }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENT, "v"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.LBRACE, "{"},
+		{token.STRING, "k"},
+		{token.COLON, ":"},
+		{token.IDENT, "x"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.RBRACE, "}"},
+		{token.FAT_ARROW, "=>"},
+		{token.IDENT, "x"},
+		{token.COMMA, ","},
+		{token.IDENT, "_"},
+		{token.FAT_ARROW, "=>"},
+		{token.INT, "0"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNextTokenStructs tests the struct keyword and the . used for field access
+func TestNextTokenStructs(t *testing.T) {
+	input := `struct Point { x } p.x ...rest`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRUCT, "struct"},
+		{token.IDENT, "Point"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "p"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenTypeAnnotations(t *testing.T) {
+	input := `fn(a: int) -> bool a - >b`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FUNCTION, "fn"},
+		{token.LPAREN, "("},
+		{token.IDENT, "a"},
+		{token.COLON, ":"},
+		{token.IDENT, "int"},
+		{token.RPAREN, ")"},
+		{token.ARROW, "->"},
+		{token.IDENT, "bool"},
+		{token.IDENT, "a"},
+		{token.MINUS, "-"},
+		{token.GT, ">"},
+		{token.IDENT, "b"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenDialects(t *testing.T) {
+	tests := []struct {
+		input    string         // The source
+		options  []lexer.Option // The lexer options
+		expected []token.Token  // The tokens before EOF (type and literal only)
+	}{
+		{"yo x = facts;", nil, []token.Token{{Type: token.IDENT, Literal: "yo"}, {Type: token.IDENT, Literal: "x"}, {Type: token.ASSIGN, Literal: "="}, {Type: token.IDENT, Literal: "facts"}, {Type: token.SEMICOLON, Literal: ";"}}},
+		{"yo x = facts;", []lexer.Option{lexer.WithDialect(token.Bro)}, []token.Token{{Type: token.LET, Literal: "yo"}, {Type: token.IDENT, Literal: "x"}, {Type: token.ASSIGN, Literal: "="}, {Type: token.TRUE, Literal: "facts"}, {Type: token.SEMICOLON, Literal: ";"}}},
+		{"let bruh", []lexer.Option{lexer.WithDialect(token.Bro)}, []token.Token{{Type: token.IDENT, Literal: "let"}, {Type: token.RETURN, Literal: "bruh"}}},
+		{"// dialect: bro\nbro nah", nil, []token.Token{{Type: token.FUNCTION, Literal: "bro"}, {Type: token.FALSE, Literal: "nah"}}},
+		{"// dialect: classic\nfn yo", []lexer.Option{lexer.WithDialect(token.Bro)}, []token.Token{{Type: token.FUNCTION, Literal: "fn"}, {Type: token.IDENT, Literal: "yo"}}}, // The pragma wins over the option
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input, tt.options...)
+		if l.Err() != nil {
+			t.Fatalf("%q: unexpected error %s", tt.input, l.Err())
+		}
+
+		for i, expected := range tt.expected {
+			tok := l.NextToken()
+			if tok.Type != expected.Type || tok.Literal != expected.Literal {
+				t.Fatalf("%q: tokens[%d] wrong. expected=%s %q, got=%s %q", tt.input, i, expected.Type, expected.Literal, tok.Type, tok.Literal)
+			}
+		}
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Fatalf("%q: expected EOF, got=%s %q", tt.input, tok.Type, tok.Literal)
+		}
+	}
+
+	if l := lexer.New("// dialect: nope\nlet x = 1;"); l.Err() == nil {
+		t.Errorf("expected an error for an unknown dialect")
+	}
+}
+
+func TestNextTokenOffsets(t *testing.T) {
+	input := "let é = \"ü\";\n  x"
+	expected := []int{0, 4, 7, 9, 13, 17, 18} // Byte offsets of let, é, =, "ü", ;, x and EOF
+
+	l := lexer.New(input)
+	for i, offset := range expected {
+		tok := l.NextToken()
+		if tok.Offset != offset {
+			t.Fatalf("tokens[%d] %q - offset wrong. expected=%d, got=%d", i, tok.Literal, offset, tok.Offset)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kriptonian1/BroLang/src/token"
+	"github.com/kriptonian1/BroLang/src/translate"
+)
+
+func TestTranslate(t *testing.T) {
+	classic := `// dialect: classic
+/// Counts down.
+let countdown = fn(n) {
+  // stop at zero, return null
+  match (n == 0) { true => null, false => countdown(n - 1) };
+};
+let msg = "let ${true} fn";
+`
+	bro := `// dialect: bro
+/// Counts down.
+yo countdown = bro(n) {
+  // stop at zero, return null
+  vibe (n == 0) { facts => ghosted, nah => countdown(n - 1) };
+};
+yo msg = "let ${facts} fn";
+`
+
+	got, err := translate.Translate(classic, token.Classic, token.Bro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != bro {
+		t.Errorf("classic to bro wrong. expected=\n%s\ngot=\n%s", bro, got)
+	}
+
+	back, err := translate.Translate(got, token.Classic, token.Classic) // The pragma says the source is bro
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back != classic {
+		t.Errorf("bro to classic is not the original. expected=\n%s\ngot=\n%s", classic, back)
+	}
+
+	unmarked := strings.TrimPrefix(classic, "// dialect: classic\n") // Classic needs no pragma, but gets one once translated
+	if got, err := translate.Translate(unmarked, token.Classic, token.Bro); err != nil || got != bro {
+		t.Errorf("classic without a pragma to bro wrong. got=\n%s (%v)", got, err)
+	}
+	if got, err := translate.Translate(unmarked, token.Classic, token.Classic); err != nil || got != unmarked {
+		t.Errorf("classic without a pragma to classic gained a pragma. got=\n%s (%v)", got, err)
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := []struct {
+		input    string         // The source, in the classic dialect unless it has a pragma
+		to       *token.Dialect // The dialect to translate into
+		expected string         // The error
+	}{
+		{"let yo = 1;", token.Bro, "yo at 1:5 is a keyword in the bro dialect; rename it before translating"},
+		{"// dialect: bro\nyo let = 1;", token.Classic, "let at 2:4 is a keyword in the classic dialect; rename it before translating"},
+		{"// dialect: slang\nlet x = 1;", token.Bro, `unknown dialect "slang" at 1:1 (known dialects: bro, classic)`},
+	}
+
+	for _, tt := range tests {
+		_, err := translate.Translate(tt.input, token.Classic, tt.to)
+		if err == nil {
+			t.Fatalf("%q: expected an error", tt.input)
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, err.Error())
+		}
+	}
+}